@@ -0,0 +1,182 @@
+// Package api expõe os produtos e as vendas em uma API HTTP com JSON, para que outros
+// sistemas, como um painel web, leiam e alterem os mesmos dados do menu interativo:
+//
+//	GET    /produtos             lista os produtos (filtros: nome, categoria, sku, min, max; ordem)
+//	POST   /produtos             cadastra um produto
+//	GET    /produtos/{id}        exibe um produto
+//	PUT    /produtos/{id}        altera um produto
+//	DELETE /produtos/{id}        remove um produto
+//	GET    /vendas               lista as vendas (filtros: status, cliente, de, ate)
+//	POST   /vendas               registra uma venda e, com pagamentos, finaliza-a
+//	GET    /vendas/{id}          exibe uma venda
+//	PUT    /vendas/{id}          altera os itens de uma venda aberta
+//	DELETE /vendas/{id}          descarta uma venda aberta
+//	POST   /vendas/{id}/finalizar finaliza uma venda aberta com os pagamentos informados
+//	POST   /vendas/{id}/cancelar  cancela uma venda
+//
+// As listagens são paginadas por cursor: o parâmetro limite (padrão 50, até 500) define o
+// tamanho da página, e o cursor retornado em ProximoCursor continua a listagem após o
+// último item da página, mesmo que ele tenha sido alterado ou removido nesse intervalo.
+// O cursor só vale para a mesma ordem em que foi gerado.
+//
+// Os erros são retornados como {"Erro": "mensagem"}, com o código HTTP correspondente
+// (ver statusDoErro): 400 para requisições malformadas ou dados recusados pela validação,
+// 404 para registros inexistentes, 409 para conflitos com o estado atual e 422 para
+// operações recusadas pelas regras do negócio, como estoque ou pagamento insuficientes.
+package api
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Limites da paginação das listagens.
+const (
+	limitePadrao = 50
+	limiteMaximo = 500
+)
+
+// tamanhoMaximoCorpo limita o corpo das requisições, em bytes.
+const tamanhoMaximoCorpo = 1 << 20
+
+// ErrRequisicao indica uma requisição malformada, como um JSON inválido ou um parâmetro
+// com formato incorreto; resulta no código 400.
+var ErrRequisicao = errors.New("requisição inválida")
+
+// Servidor atende às requisições da API com os DAOs de produtos e de vendas.
+type Servidor struct {
+	produtos *data.DAOProduto
+	vendas   *data.DAOVenda
+	rotas    *http.ServeMux
+}
+
+// NewServidor cria uma nova instância de Servidor com as rotas da API.
+func NewServidor() *Servidor {
+	s := &Servidor{
+		produtos: data.GetInstance(),
+		vendas:   data.GetVendaInstance(),
+		rotas:    http.NewServeMux(),
+	}
+	s.rotas.HandleFunc("GET /produtos", s.listarProdutos)
+	s.rotas.HandleFunc("POST /produtos", s.adicionarProduto)
+	s.rotas.HandleFunc("GET /produtos/{id}", s.buscarProduto)
+	s.rotas.HandleFunc("PUT /produtos/{id}", s.atualizarProduto)
+	s.rotas.HandleFunc("DELETE /produtos/{id}", s.removerProduto)
+	s.rotas.HandleFunc("GET /vendas", s.listarVendas)
+	s.rotas.HandleFunc("POST /vendas", s.adicionarVenda)
+	s.rotas.HandleFunc("GET /vendas/{id}", s.buscarVenda)
+	s.rotas.HandleFunc("PUT /vendas/{id}", s.atualizarVenda)
+	s.rotas.HandleFunc("DELETE /vendas/{id}", s.removerVenda)
+	s.rotas.HandleFunc("POST /vendas/{id}/finalizar", s.finalizarVenda)
+	s.rotas.HandleFunc("POST /vendas/{id}/cancelar", s.cancelarVenda)
+	return s
+}
+
+// ServeHTTP implementa a interface http.Handler.
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.rotas.ServeHTTP(w, r)
+}
+
+// Pagina é o corpo das respostas das listagens.
+type Pagina[E any] struct {
+	Itens         []E
+	Total         int    // Quantidade de registros que atendem aos filtros, em todas as páginas.
+	ProximoCursor string `json:",omitempty"` // Vazio na última página.
+}
+
+// paginar executa a consulta com o limite e o cursor dos parâmetros da requisição.
+func paginar[E entidades.Entidade](r *http.Request, consulta *data.Consulta[E]) (*Pagina[E], error) {
+	limite := limitePadrao
+	if texto := r.URL.Query().Get("limite"); texto != "" {
+		n, err := strconv.Atoi(texto)
+		if err != nil || n <= 0 || n > limiteMaximo {
+			return nil, fmt.Errorf("%w: limite deve estar entre 1 e %d", ErrRequisicao, limiteMaximo)
+		}
+		limite = n
+	}
+	total := consulta.Contar()
+	pagina, err := consulta.Limite(limite).Apos(r.URL.Query().Get("cursor")).Paginar()
+	if errors.Is(err, data.ErrCursorInvalido) {
+		return nil, fmt.Errorf("%w: %v", ErrRequisicao, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pagina.Itens == nil {
+		pagina.Itens = []E{}
+	}
+	return &Pagina[E]{Itens: pagina.Itens, Total: total, ProximoCursor: pagina.ProximoCursor}, nil
+}
+
+// lerID retorna o ID do caminho da requisição.
+func lerID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: ID inválido: %q", ErrRequisicao, r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// lerCorpo decodifica o corpo JSON da requisição em destino, recusando campos desconhecidos.
+func lerCorpo(w http.ResponseWriter, r *http.Request, destino any) error {
+	decodificador := json.NewDecoder(http.MaxBytesReader(w, r.Body, tamanhoMaximoCorpo))
+	decodificador.DisallowUnknownFields()
+	if err := decodificador.Decode(destino); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: corpo vazio", ErrRequisicao)
+		}
+		return fmt.Errorf("%w: %v", ErrRequisicao, err)
+	}
+	if decodificador.More() {
+		return fmt.Errorf("%w: o corpo deve conter um único objeto JSON", ErrRequisicao)
+	}
+	return nil
+}
+
+// responder escreve valor como JSON com o código informado.
+func responder(w http.ResponseWriter, status int, valor any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	codificador := json.NewEncoder(w)
+	codificador.SetIndent("", "  ")
+	codificador.Encode(valor)
+}
+
+// responderErro escreve o erro como {"Erro": "mensagem"}, com o código correspondente.
+func responderErro(w http.ResponseWriter, err error) {
+	responder(w, statusDoErro(err), map[string]string{"Erro": err.Error()})
+}
+
+// statusDoErro retorna o código HTTP correspondente a um erro dos DAOs ou da API.
+func statusDoErro(err error) int {
+	var conflito *data.ErroConflito
+	var tamanho *http.MaxBytesError
+	switch {
+	case errors.As(err, &tamanho):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrRequisicao),
+		errors.Is(err, data.ErrInvalido):
+		return http.StatusBadRequest
+	case errors.Is(err, data.ErrNaoEncontrado):
+		return http.StatusNotFound
+	case errors.As(err, &conflito),
+		errors.Is(err, data.ErrDuplicado),
+		errors.Is(err, data.ErrVendaEncerrada),
+		errors.Is(err, data.ErrCaixaFechado),
+		errors.Is(err, entidades.ErrTransicaoInvalida):
+		return http.StatusConflict
+	case errors.Is(err, data.ErrEstoqueInsuficiente),
+		errors.Is(err, data.ErrDescontoExcedido),
+		errors.Is(err, data.ErrPontosInsuficientes),
+		errors.Is(err, entidades.ErrPagamentoInsuficiente):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// TestMain grava os dados dos DAOs em um diretório temporário e abre o caixa, necessário
+// para registrar vendas.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "api")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("CLP_DADOS", dir)
+	if _, err := data.GetCaixaInstance().Abrir("teste", entidades.Reais(0)); err != nil {
+		panic(err)
+	}
+	codigo := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(codigo)
+}
+
+// requisitar envia a requisição ao servidor, com corpo codificado como JSON se não for nil.
+func requisitar(t *testing.T, servidor http.Handler, metodo, caminho string, corpo any) *httptest.ResponseRecorder {
+	t.Helper()
+	var leitor bytes.Buffer
+	if corpo != nil {
+		if err := json.NewEncoder(&leitor).Encode(corpo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resposta := httptest.NewRecorder()
+	servidor.ServeHTTP(resposta, httptest.NewRequest(metodo, caminho, &leitor))
+	return resposta
+}
+
+// esperar falha o teste se a resposta não tiver o código esperado e, senão, decodifica o
+// corpo em destino, se não for nil.
+func esperar(t *testing.T, resposta *httptest.ResponseRecorder, status int, destino any) {
+	t.Helper()
+	if resposta.Code != status {
+		t.Fatalf("código %d, esperado %d: %s", resposta.Code, status, resposta.Body)
+	}
+	if destino != nil {
+		if err := json.Unmarshal(resposta.Body.Bytes(), destino); err != nil {
+			t.Fatalf("corpo inválido: %v: %s", err, resposta.Body)
+		}
+	}
+}
+
+func TestStatusDoErro(t *testing.T) {
+	casos := []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("%w: corpo vazio", ErrRequisicao), http.StatusBadRequest},
+		{fmt.Errorf("%w: o nome do produto é obrigatório", data.ErrInvalido), http.StatusBadRequest},
+		{data.ErrNaoEncontrado, http.StatusNotFound},
+		{&data.ErroConflito{ID: 1, VersaoEsperada: 1, VersaoAtual: 2}, http.StatusConflict},
+		{data.ErrDuplicado, http.StatusConflict},
+		{data.ErrVendaEncerrada, http.StatusConflict},
+		{data.ErrCaixaFechado, http.StatusConflict},
+		{&data.ErroEstoqueInsuficiente{Produto: "Caneca", Disponivel: 1, Solicitado: 2}, http.StatusUnprocessableEntity},
+		{data.ErrDescontoExcedido, http.StatusUnprocessableEntity},
+		{data.ErrPontosInsuficientes, http.StatusUnprocessableEntity},
+		{entidades.ErrPagamentoInsuficiente, http.StatusUnprocessableEntity},
+		{errors.New("falha de gravação"), http.StatusInternalServerError},
+	}
+	for _, c := range casos {
+		if status := statusDoErro(fmt.Errorf("contexto: %w", c.err)); status != c.status {
+			t.Errorf("statusDoErro(%v) = %d, esperado %d", c.err, status, c.status)
+		}
+	}
+}
+
+func TestRespostasDeErro(t *testing.T) {
+	servidor := NewServidor()
+	casos := []struct {
+		metodo, caminho string
+		corpo           any
+		status          int
+	}{
+		{http.MethodGet, "/produtos/abc", nil, http.StatusBadRequest},
+		{http.MethodGet, "/produtos/999999", nil, http.StatusNotFound},
+		{http.MethodPost, "/produtos", nil, http.StatusBadRequest},
+		{http.MethodPost, "/produtos", map[string]any{"Nome": "Caneca", "Valor": 10, "Cor": "azul"}, http.StatusBadRequest},
+		{http.MethodPost, "/produtos", map[string]any{"Valor": 10}, http.StatusBadRequest},
+		{http.MethodPost, "/produtos", map[string]any{"Nome": "Caneca", "Valor": 10, "Estoque": -1}, http.StatusBadRequest},
+		{http.MethodGet, "/produtos?limite=0", nil, http.StatusBadRequest},
+		{http.MethodGet, "/produtos?ordem=cor", nil, http.StatusBadRequest},
+		{http.MethodGet, "/produtos?min=abc", nil, http.StatusBadRequest},
+		{http.MethodGet, "/vendas?status=pendente", nil, http.StatusBadRequest},
+		{http.MethodGet, "/vendas?de=01/02/2024", nil, http.StatusBadRequest},
+		{http.MethodGet, "/vendas/999999", nil, http.StatusNotFound},
+		{http.MethodPost, "/vendas", map[string]any{"Itens": []any{}}, http.StatusBadRequest},
+		{http.MethodPost, "/vendas/999999/finalizar", map[string]any{"Pagamentos": []any{map[string]any{"Forma": "dinheiro"}}}, http.StatusNotFound},
+	}
+	for _, c := range casos {
+		resposta := requisitar(t, servidor, c.metodo, c.caminho, c.corpo)
+		if resposta.Code != c.status {
+			t.Errorf("%s %s: código %d, esperado %d: %s", c.metodo, c.caminho, resposta.Code, c.status, resposta.Body)
+			continue
+		}
+		var corpo map[string]string
+		if err := json.Unmarshal(resposta.Body.Bytes(), &corpo); err != nil || corpo["Erro"] == "" {
+			t.Errorf("%s %s: corpo sem a mensagem de erro: %s", c.metodo, c.caminho, resposta.Body)
+		}
+	}
+}
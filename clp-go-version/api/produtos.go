@@ -0,0 +1,194 @@
+package api
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// entradaProduto é o corpo de POST /produtos e PUT /produtos/{id}. O valor aceita um
+// número em reais, como 9.9, ou o objeto {"Centavos": 990, "Moeda": "BRL"}.
+//
+// O estoque é registrado como entrada no cadastro e, na alteração, ajustado para o valor
+// informado, constando no histórico do estoque; sem o campo, o estoque não muda. Na
+// alteração, uma Versao diferente de zero deve ser a versão atual do produto, o que
+// impede sobrescrever uma alteração feita por outro usuário.
+type entradaProduto struct {
+	Nome      string
+	Valor     entidades.Dinheiro
+	SKU       string
+	NCM       string
+	CFOP      string
+	Categoria string
+	Estoque   *int
+	Versao    int64
+}
+
+// aplicar copia os campos da entrada para o produto, exceto o estoque.
+func (e entradaProduto) aplicar(produto *entidades.Produto) {
+	produto.SetNome(strings.TrimSpace(e.Nome))
+	produto.SetValor(e.Valor)
+	produto.SetSKU(strings.TrimSpace(e.SKU))
+	produto.SetNCM(strings.TrimSpace(e.NCM))
+	produto.SetCFOP(strings.TrimSpace(e.CFOP))
+	produto.SetCategoria(strings.TrimSpace(e.Categoria))
+}
+
+// listarProdutos atende a GET /produtos. Filtros: nome (parte do nome, sem diferenciar
+// maiúsculas e acentos), categoria, sku, min e max (valor). A ordem pode ser id (padrão),
+// nome, valor ou -valor.
+func (s *Servidor) listarProdutos(w http.ResponseWriter, r *http.Request) {
+	parametros := r.URL.Query()
+	consulta := s.produtos.Consultar()
+	if nome := data.NormalizarTexto(parametros.Get("nome")); nome != "" {
+		consulta.Onde(func(p *entidades.Produto) bool { return strings.Contains(data.NormalizarTexto(p.GetNome()), nome) })
+	}
+	if categoria := data.NormalizarTexto(parametros.Get("categoria")); categoria != "" {
+		consulta.Onde(func(p *entidades.Produto) bool { return data.NormalizarTexto(p.GetCategoria()) == categoria })
+	}
+	if sku := strings.TrimSpace(parametros.Get("sku")); sku != "" {
+		consulta.Onde(func(p *entidades.Produto) bool { return strings.EqualFold(p.GetSKU(), sku) })
+	}
+	for _, limite := range []struct {
+		parametro string
+		aceitar   func(comparacao int) bool
+	}{
+		{"min", func(c int) bool { return c >= 0 }},
+		{"max", func(c int) bool { return c <= 0 }},
+	} {
+		texto := parametros.Get(limite.parametro)
+		if texto == "" {
+			continue
+		}
+		valor, err := entidades.ParseDinheiro(texto, entidades.MoedaPadrao)
+		if err != nil {
+			responderErro(w, fmt.Errorf("%w: %s: %v", ErrRequisicao, limite.parametro, err))
+			return
+		}
+		aceitar := limite.aceitar
+		consulta.Onde(func(p *entidades.Produto) bool { return aceitar(p.GetValor().Comparar(valor)) })
+	}
+	switch parametros.Get("ordem") {
+	case "", "id":
+	case "nome":
+		consulta.OrdenarPor(data.PorChave(func(p *entidades.Produto) string { return data.NormalizarTexto(p.GetNome()) }))
+	case "valor":
+		consulta.OrdenarPor(data.PorChave(func(p *entidades.Produto) int64 { return p.GetValor().Centavos }))
+	case "-valor":
+		consulta.OrdenarPor(data.Decrescente(data.PorChave(func(p *entidades.Produto) int64 { return p.GetValor().Centavos })))
+	default:
+		responderErro(w, fmt.Errorf("%w: ordem desconhecida %q (use id, nome, valor ou -valor)", ErrRequisicao, parametros.Get("ordem")))
+		return
+	}
+
+	pagina, err := paginar(r, consulta)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	responder(w, http.StatusOK, pagina)
+}
+
+// buscarProduto atende a GET /produtos/{id}.
+func (s *Servidor) buscarProduto(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	produto, err := s.produtos.Buscar(id)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	responder(w, http.StatusOK, produto)
+}
+
+// adicionarProduto atende a POST /produtos e responde com o produto cadastrado.
+func (s *Servidor) adicionarProduto(w http.ResponseWriter, r *http.Request) {
+	var entrada entradaProduto
+	if err := lerCorpo(w, r, &entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+
+	produto := entidades.NewProduto("", entidades.Dinheiro{})
+	entrada.aplicar(produto)
+	estoque := 0
+	if entrada.Estoque != nil {
+		estoque = *entrada.Estoque
+	}
+	if err := s.produtos.AdicionarComEstoque(produto, estoque, "estoque inicial"); err != nil {
+		responderErro(w, err)
+		return
+	}
+	s.responderProduto(w, http.StatusCreated, produto.GetID())
+}
+
+// atualizarProduto atende a PUT /produtos/{id}, substituindo os dados do produto pelos
+// informados, e responde com o produto alterado. Se o estoque não puder ser ajustado, os
+// demais dados também não são alterados (ver DAOProduto.AtualizarComEstoque).
+func (s *Servidor) atualizarProduto(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	var entrada entradaProduto
+	if err := lerCorpo(w, r, &entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+	encontrado, err := s.produtos.Buscar(id)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+
+	produto := encontrado.Clonar()
+	entrada.aplicar(produto)
+	if entrada.Versao != 0 {
+		produto.SetVersao(entrada.Versao)
+	}
+	switch {
+	case entrada.Estoque != nil:
+		err = s.produtos.AtualizarComEstoque(produto, *entrada.Estoque, "ajuste pela API")
+	case *produto != *encontrado:
+		err = s.produtos.Atualizar(produto)
+	}
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	s.responderProduto(w, http.StatusOK, id)
+}
+
+// removerProduto atende a DELETE /produtos/{id}.
+func (s *Servidor) removerProduto(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	if err := s.produtos.Remover(id); err != nil {
+		responderErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// responderProduto responde com o produto gravado, lido novamente para incluir o estoque
+// e a versão atualizados.
+func (s *Servidor) responderProduto(w http.ResponseWriter, status int, id int64) {
+	produto, err := s.produtos.Buscar(id)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	if status == http.StatusCreated {
+		w.Header().Set("Location", fmt.Sprintf("/produtos/%d", id))
+	}
+	responder(w, status, produto)
+}
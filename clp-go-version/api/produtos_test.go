@@ -0,0 +1,119 @@
+package api
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestCRUDProdutos(t *testing.T) {
+	servidor := NewServidor()
+
+	var produto entidades.Produto
+	resposta := requisitar(t, servidor, http.MethodPost, "/produtos", map[string]any{"Nome": "Caneca CRUD", "Valor": 25, "Estoque": 5, "Categoria": "crud"})
+	esperar(t, resposta, http.StatusCreated, &produto)
+	caminho := fmt.Sprintf("/produtos/%d", produto.GetID())
+	if local := resposta.Header().Get("Location"); local != caminho {
+		t.Errorf("Location = %q, esperado %q", local, caminho)
+	}
+	if produto.GetValor() != entidades.Reais(2500) || produto.GetEstoque() != 5 {
+		t.Errorf("produto cadastrado com valor %s e estoque %d, esperado R$ 25,00 e 5", produto.GetValor(), produto.GetEstoque())
+	}
+
+	var lido entidades.Produto
+	esperar(t, requisitar(t, servidor, http.MethodGet, caminho, nil), http.StatusOK, &lido)
+	if lido != produto {
+		t.Errorf("GET = %+v, esperado %+v", lido, produto)
+	}
+
+	alteracao := map[string]any{"Nome": "Caneca CRUD", "Valor": 30, "Estoque": 8, "Categoria": "crud", "Versao": produto.GetVersao()}
+	esperar(t, requisitar(t, servidor, http.MethodPut, caminho, alteracao), http.StatusOK, &lido)
+	if lido.GetValor() != entidades.Reais(3000) || lido.GetEstoque() != 8 {
+		t.Errorf("produto alterado com valor %s e estoque %d, esperado R$ 30,00 e 8", lido.GetValor(), lido.GetEstoque())
+	}
+	// A versão lida antes da alteração está desatualizada.
+	esperar(t, requisitar(t, servidor, http.MethodPut, caminho, alteracao), http.StatusConflict, nil)
+
+	// Um estoque recusado impede também a alteração dos demais dados.
+	recusada := map[string]any{"Nome": "Caneca CRUD", "Valor": 99, "Estoque": -1, "Versao": lido.GetVersao()}
+	esperar(t, requisitar(t, servidor, http.MethodPut, caminho, recusada), http.StatusBadRequest, nil)
+	var inalterado entidades.Produto
+	esperar(t, requisitar(t, servidor, http.MethodGet, caminho, nil), http.StatusOK, &inalterado)
+	if inalterado != lido {
+		t.Errorf("produto %+v após a alteração recusada, esperado %+v", inalterado, lido)
+	}
+
+	esperar(t, requisitar(t, servidor, http.MethodDelete, caminho, nil), http.StatusNoContent, nil)
+	esperar(t, requisitar(t, servidor, http.MethodGet, caminho, nil), http.StatusNotFound, nil)
+	esperar(t, requisitar(t, servidor, http.MethodDelete, caminho, nil), http.StatusNotFound, nil)
+}
+
+func TestProdutoComSKUDuplicado(t *testing.T) {
+	servidor := NewServidor()
+	corpo := map[string]any{"Nome": "Bloco SKU", "Valor": 8, "SKU": "BLOCO-DUP"}
+	esperar(t, requisitar(t, servidor, http.MethodPost, "/produtos", corpo), http.StatusCreated, nil)
+	corpo["Nome"] = "Outro bloco SKU"
+	esperar(t, requisitar(t, servidor, http.MethodPost, "/produtos", corpo), http.StatusConflict, nil)
+}
+
+func TestListarProdutosFiltradosEPaginados(t *testing.T) {
+	servidor := NewServidor()
+	for i := 1; i <= 5; i++ {
+		corpo := map[string]any{"Nome": fmt.Sprintf("Lápis %d", i), "Valor": 10 * i, "Categoria": "paginação"}
+		esperar(t, requisitar(t, servidor, http.MethodPost, "/produtos", corpo), http.StatusCreated, nil)
+	}
+	esperar(t, requisitar(t, servidor, http.MethodPost, "/produtos", map[string]any{"Nome": "Lápis de outra categoria", "Valor": 20}), http.StatusCreated, nil)
+
+	var valores []int64
+	cursor := ""
+	for paginas := 1; ; paginas++ {
+		parametros := url.Values{"categoria": {"Paginacao"}, "ordem": {"-valor"}, "limite": {"2"}, "cursor": {cursor}}
+		var pagina Pagina[*entidades.Produto]
+		esperar(t, requisitar(t, servidor, http.MethodGet, "/produtos?"+parametros.Encode(), nil), http.StatusOK, &pagina)
+		if pagina.Total != 5 || len(pagina.Itens) > 2 {
+			t.Fatalf("página %d com %d itens de %d, esperado até 2 de 5", paginas, len(pagina.Itens), pagina.Total)
+		}
+		for _, p := range pagina.Itens {
+			valores = append(valores, p.GetValor().Centavos)
+		}
+		if pagina.ProximoCursor == "" {
+			if paginas != 3 {
+				t.Errorf("%d páginas, esperado 3", paginas)
+			}
+			break
+		}
+		cursor = pagina.ProximoCursor
+	}
+	if !slices.Equal(valores, []int64{5000, 4000, 3000, 2000, 1000}) {
+		t.Errorf("valores = %v, esperado do maior para o menor", valores)
+	}
+
+	var pagina Pagina[*entidades.Produto]
+	esperar(t, requisitar(t, servidor, http.MethodGet, "/produtos?categoria=paginacao&min=20&max=40,00", nil), http.StatusOK, &pagina)
+	if pagina.Total != 3 {
+		t.Errorf("%d produtos entre R$ 20,00 e R$ 40,00, esperado 3", pagina.Total)
+	}
+	esperar(t, requisitar(t, servidor, http.MethodGet, "/produtos?nome=LAPIS+3", nil), http.StatusOK, &pagina)
+	if pagina.Total != 1 || pagina.Itens[0].GetNome() != "Lápis 3" {
+		t.Errorf("busca pelo nome retornou %+v, esperado o Lápis 3", pagina.Itens)
+	}
+}
+
+func TestCursorDeOutraOrdemERecusado(t *testing.T) {
+	servidor := NewServidor()
+	for i := 1; i <= 3; i++ {
+		corpo := map[string]any{"Nome": fmt.Sprintf("Borracha %d", i), "Valor": i, "Categoria": "cursor"}
+		esperar(t, requisitar(t, servidor, http.MethodPost, "/produtos", corpo), http.StatusCreated, nil)
+	}
+
+	var pagina Pagina[*entidades.Produto]
+	esperar(t, requisitar(t, servidor, http.MethodGet, "/produtos?categoria=cursor&ordem=nome&limite=1", nil), http.StatusOK, &pagina)
+	if pagina.ProximoCursor == "" {
+		t.Fatal("primeira página sem cursor")
+	}
+	esperar(t, requisitar(t, servidor, http.MethodGet, "/produtos?categoria=cursor&ordem=valor&cursor="+pagina.ProximoCursor, nil), http.StatusBadRequest, nil)
+	esperar(t, requisitar(t, servidor, http.MethodGet, "/produtos?categoria=cursor&cursor=inv%C3%A1lido", nil), http.StatusBadRequest, nil)
+}
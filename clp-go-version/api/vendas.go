@@ -0,0 +1,346 @@
+package api
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatoData é o formato dos parâmetros de data das listagens.
+const formatoData = "2006-01-02"
+
+// entradaItem é um item de entradaVenda. O produto é indicado pelo ProdutoID ou, sem ele,
+// pelo nome ou SKU em Produto.
+type entradaItem struct {
+	ProdutoID  int64
+	Produto    string
+	Quantidade int
+}
+
+// entradaVenda é o corpo de POST /vendas e PUT /vendas/{id}. Cliente é o CPF ou CNPJ de um
+// cliente cadastrado. Na criação, os pagamentos, se houver, finalizam a venda; um pagamento
+// sem valor paga o restante. Na alteração, os itens e o cliente substituem os da venda,
+// descartando os descontos de item; um Cliente vazio remove o cliente da venda, e uma
+// Versao diferente de zero deve ser a versão atual da venda.
+type entradaVenda struct {
+	Itens      []entradaItem
+	Cliente    string
+	Pagamentos []entidades.Pagamento
+	Versao     int64
+}
+
+// entradaFinalizacao é o corpo de POST /vendas/{id}/finalizar.
+type entradaFinalizacao struct {
+	Pagamentos []entidades.Pagamento
+}
+
+// entradaCancelamento é o corpo de POST /vendas/{id}/cancelar.
+type entradaCancelamento struct {
+	Motivo string
+}
+
+// listarVendas atende a GET /vendas, das vendas mais recentes às mais antigas. Filtros:
+// status, cliente (ID), caixa (ID), de e ate (datas de abertura no formato aaaa-mm-dd,
+// inclusive).
+func (s *Servidor) listarVendas(w http.ResponseWriter, r *http.Request) {
+	parametros := r.URL.Query()
+	consulta := s.vendas.Consultar().
+		OrdenarPor(data.Decrescente(data.PorChave(func(v *entidades.Venda) int64 { return v.GetDataHora().UnixNano() })))
+
+	if texto := parametros.Get("status"); texto != "" {
+		status := entidades.StatusVenda(strings.ToLower(texto))
+		if !slices.Contains([]entidades.StatusVenda{entidades.VendaAberta, entidades.VendaFinalizada, entidades.VendaCancelada, entidades.VendaEstornada}, status) {
+			responderErro(w, fmt.Errorf("%w: status desconhecido %q", ErrRequisicao, texto))
+			return
+		}
+		consulta.Onde(func(v *entidades.Venda) bool { return v.GetStatus() == status })
+	}
+	for _, filtro := range []struct {
+		parametro string
+		id        func(*entidades.Venda) int64
+	}{
+		{"cliente", func(v *entidades.Venda) int64 { return v.ClienteID }},
+		{"caixa", func(v *entidades.Venda) int64 { return v.CaixaID }},
+	} {
+		texto := parametros.Get(filtro.parametro)
+		if texto == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(texto, 10, 64)
+		if err != nil {
+			responderErro(w, fmt.Errorf("%w: %s: ID inválido: %q", ErrRequisicao, filtro.parametro, texto))
+			return
+		}
+		chave := filtro.id
+		consulta.Onde(func(v *entidades.Venda) bool { return chave(v) == id })
+	}
+	if texto := parametros.Get("de"); texto != "" {
+		de, err := time.ParseInLocation(formatoData, texto, time.Local)
+		if err != nil {
+			responderErro(w, fmt.Errorf("%w: de: data inválida %q (use aaaa-mm-dd)", ErrRequisicao, texto))
+			return
+		}
+		consulta.Onde(func(v *entidades.Venda) bool { return !v.GetDataHora().Before(de) })
+	}
+	if texto := parametros.Get("ate"); texto != "" {
+		ate, err := time.ParseInLocation(formatoData, texto, time.Local)
+		if err != nil {
+			responderErro(w, fmt.Errorf("%w: ate: data inválida %q (use aaaa-mm-dd)", ErrRequisicao, texto))
+			return
+		}
+		consulta.Onde(func(v *entidades.Venda) bool { return v.GetDataHora().Before(ate.AddDate(0, 0, 1)) })
+	}
+
+	pagina, err := paginar(r, consulta)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	responder(w, http.StatusOK, pagina)
+}
+
+// buscarVenda atende a GET /vendas/{id}.
+func (s *Servidor) buscarVenda(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	venda, err := s.vendas.Buscar(id)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	responder(w, http.StatusOK, venda)
+}
+
+// adicionarVenda atende a POST /vendas e responde com a venda registrada. Assim como no
+// menu, a venda exige um caixa aberto. Com pagamentos, a venda só é registrada se puder
+// ser finalizada (ver DAOVenda.AdicionarFinalizada).
+func (s *Servidor) adicionarVenda(w http.ResponseWriter, r *http.Request) {
+	var entrada entradaVenda
+	if err := lerCorpo(w, r, &entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+	if entrada.Versao != 0 {
+		responderErro(w, fmt.Errorf("%w: Versao só é aceita na alteração", ErrRequisicao))
+		return
+	}
+	venda := entidades.NewVenda()
+	if err := s.preencherVenda(venda, entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+	if len(entrada.Pagamentos) > 0 {
+		// Os pagamentos são lidos sobre a venda com as promoções que ela receberá, para que
+		// um pagamento sem valor pague o restante correto.
+		pagamentos, err := lerPagamentos(s.vendas.Previa(venda), entrada.Pagamentos)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+		if venda, err = s.vendas.AdicionarFinalizada(venda, pagamentos); err != nil {
+			responderErro(w, err)
+			return
+		}
+	} else if err := s.vendas.Adicionar(venda); err != nil {
+		responderErro(w, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/vendas/%d", venda.GetID()))
+	responder(w, http.StatusCreated, venda)
+}
+
+// atualizarVenda atende a PUT /vendas/{id}, substituindo os itens e o cliente de uma venda
+// aberta, e responde com a venda alterada.
+func (s *Servidor) atualizarVenda(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	var entrada entradaVenda
+	if err := lerCorpo(w, r, &entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+	if len(entrada.Pagamentos) > 0 {
+		responderErro(w, fmt.Errorf("%w: use POST /vendas/%d/finalizar para informar os pagamentos", ErrRequisicao, id))
+		return
+	}
+	encontrada, err := s.vendas.Buscar(id)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+
+	venda := encontrada.Clonar()
+	venda.Itens = []entidades.ItemVenda{}
+	if err := s.preencherVenda(venda, entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+	if entrada.Versao != 0 {
+		venda.SetVersao(entrada.Versao)
+	}
+	if err := s.vendas.Atualizar(venda); err != nil {
+		responderErro(w, err)
+		return
+	}
+	responder(w, http.StatusOK, venda)
+}
+
+// removerVenda atende a DELETE /vendas/{id}, descartando uma venda aberta. As vendas
+// finalizadas permanecem no histórico e devem ser canceladas.
+func (s *Servidor) removerVenda(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	if err := s.vendas.Remover(id); err != nil {
+		responderErro(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// finalizarVenda atende a POST /vendas/{id}/finalizar.
+func (s *Servidor) finalizarVenda(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	var entrada entradaFinalizacao
+	if err := lerCorpo(w, r, &entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+	venda, err := s.finalizar(id, entrada.Pagamentos)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	responder(w, http.StatusOK, venda)
+}
+
+// cancelarVenda atende a POST /vendas/{id}/cancelar.
+func (s *Servidor) cancelarVenda(w http.ResponseWriter, r *http.Request) {
+	id, err := lerID(r)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	var entrada entradaCancelamento
+	if err := lerCorpo(w, r, &entrada); err != nil {
+		responderErro(w, err)
+		return
+	}
+	if strings.TrimSpace(entrada.Motivo) == "" {
+		responderErro(w, fmt.Errorf("%w: informe o motivo do cancelamento", data.ErrInvalido))
+		return
+	}
+	venda, err := s.vendas.Cancelar(id, strings.TrimSpace(entrada.Motivo))
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+	responder(w, http.StatusOK, venda)
+}
+
+// finalizar valida os pagamentos sobre a venda atual e a finaliza.
+func (s *Servidor) finalizar(id int64, pagamentos []entidades.Pagamento) (*entidades.Venda, error) {
+	venda, err := s.vendas.Buscar(id)
+	if err != nil {
+		return nil, err
+	}
+	if !venda.Aberta() {
+		return nil, &entidades.ErroTransicao{De: venda.GetStatus(), Para: entidades.VendaFinalizada}
+	}
+	lidos, err := lerPagamentos(venda.Clonar(), pagamentos)
+	if err != nil {
+		return nil, err
+	}
+	return s.vendas.Finalizar(id, lidos)
+}
+
+// preencherVenda acrescenta à venda os itens da entrada e identifica o cliente informado;
+// sem cliente na entrada, a venda fica sem cliente. Os produtos e o cliente não encontrados
+// tornam a entrada inválida.
+func (s *Servidor) preencherVenda(venda *entidades.Venda, entrada entradaVenda) error {
+	if len(entrada.Itens) == 0 {
+		return fmt.Errorf("%w: informe ao menos um item", data.ErrInvalido)
+	}
+	if documento := strings.TrimSpace(entrada.Cliente); documento != "" {
+		cliente, err := data.GetClienteInstance().BuscarPorDocumento(documento)
+		if err != nil {
+			return fmt.Errorf("%w: cliente: %v", data.ErrInvalido, err)
+		}
+		venda.SetCliente(cliente)
+	} else {
+		venda.SetCliente(nil)
+	}
+	for i, item := range entrada.Itens {
+		if item.Quantidade <= 0 {
+			return fmt.Errorf("%w: item %d: a quantidade deve ser maior que zero", data.ErrInvalido, i+1)
+		}
+		produto, err := s.buscarItem(item)
+		if err != nil {
+			return fmt.Errorf("%w: item %d: %v", data.ErrInvalido, i+1, err)
+		}
+		venda.AdicionarItem(*produto, item.Quantidade)
+	}
+	return nil
+}
+
+// buscarItem retorna o produto do item, pelo ID ou, sem ele, pelo nome e depois pelo SKU.
+func (s *Servidor) buscarItem(item entradaItem) (*entidades.Produto, error) {
+	if item.ProdutoID != 0 {
+		return s.produtos.Buscar(item.ProdutoID)
+	}
+	if strings.TrimSpace(item.Produto) == "" {
+		return nil, errors.New("informe o ProdutoID ou o Produto (nome ou SKU)")
+	}
+	produto, err := s.produtos.BuscarPorNome(item.Produto)
+	if errors.Is(err, data.ErrNaoEncontrado) {
+		if porSKU, errSKU := s.produtos.BuscarPorSKU(item.Produto); errSKU == nil {
+			return porSKU, nil
+		}
+	}
+	return produto, err
+}
+
+// lerPagamentos aplica os pagamentos à venda informada, na ordem, para validá-los, e
+// retorna os pagamentos resultantes. Um pagamento sem valor paga o restante.
+func lerPagamentos(venda *entidades.Venda, pagamentos []entidades.Pagamento) ([]entidades.Pagamento, error) {
+	if len(pagamentos) == 0 {
+		return nil, fmt.Errorf("%w: informe ao menos um pagamento", data.ErrInvalido)
+	}
+	venda.RemoverPagamentos()
+	for i, p := range pagamentos {
+		forma, err := entidades.ParseFormaPagamento(string(p.Forma))
+		if err != nil {
+			return nil, fmt.Errorf("%w: pagamento %d: %v", data.ErrInvalido, i+1, err)
+		}
+		valor := p.Valor
+		if valor.Zerado() {
+			valor = venda.Restante()
+		}
+		pagamento := entidades.NewPagamento(forma, valor)
+		if p.Parcelas != 0 {
+			pagamento.Parcelas = p.Parcelas
+		}
+		if err := venda.AdicionarPagamento(pagamento); err != nil {
+			return nil, fmt.Errorf("%w: pagamento %d: %v", data.ErrInvalido, i+1, err)
+		}
+	}
+	return venda.Pagamentos, nil
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bytes"
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// cadastrarProduto cadastra um produto pela API e retorna o seu ID.
+func cadastrarProduto(t *testing.T, servidor http.Handler, nome string, valor, estoque int) int64 {
+	t.Helper()
+	var produto entidades.Produto
+	corpo := map[string]any{"Nome": nome, "Valor": valor, "Estoque": estoque}
+	esperar(t, requisitar(t, servidor, http.MethodPost, "/produtos", corpo), http.StatusCreated, &produto)
+	return produto.GetID()
+}
+
+func TestCRUDVendasPorHTTP(t *testing.T) {
+	servidor := httptest.NewServer(NewServidor())
+	defer servidor.Close()
+	enviar := func(metodo, caminho string, corpo any, status int, destino any) {
+		t.Helper()
+		var leitor bytes.Buffer
+		if corpo != nil {
+			json.NewEncoder(&leitor).Encode(corpo)
+		}
+		requisicao, err := http.NewRequest(metodo, servidor.URL+caminho, &leitor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resposta, err := servidor.Client().Do(requisicao)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resposta.Body.Close()
+		if resposta.StatusCode != status {
+			var erro map[string]string
+			json.NewDecoder(resposta.Body).Decode(&erro)
+			t.Fatalf("%s %s: código %d, esperado %d: %s", metodo, caminho, resposta.StatusCode, status, erro["Erro"])
+		}
+		if destino != nil {
+			if err := json.NewDecoder(resposta.Body).Decode(destino); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	produtoID := cadastrarProduto(t, servidor.Config.Handler, "Caderno de vendas", 20, 10)
+	estoque := func() int {
+		t.Helper()
+		var produto entidades.Produto
+		enviar(http.MethodGet, fmt.Sprintf("/produtos/%d", produtoID), nil, http.StatusOK, &produto)
+		return produto.GetEstoque()
+	}
+
+	var venda entidades.Venda
+	enviar(http.MethodPost, "/vendas", map[string]any{"Itens": []any{map[string]any{"ProdutoID": produtoID, "Quantidade": 2}}}, http.StatusCreated, &venda)
+	caminho := fmt.Sprintf("/vendas/%d", venda.GetID())
+	if !venda.Aberta() || estoque() != 8 {
+		t.Errorf("venda %s com estoque %d, esperado aberta e 8", venda.GetStatus(), estoque())
+	}
+
+	alteracao := map[string]any{"Itens": []any{map[string]any{"Produto": "Caderno de vendas", "Quantidade": 3}}, "Versao": venda.GetVersao()}
+	enviar(http.MethodPut, caminho, alteracao, http.StatusOK, &venda)
+	if venda.Total() != entidades.Reais(6000) || estoque() != 7 {
+		t.Errorf("venda alterada com total %s e estoque %d, esperado R$ 60,00 e 7", venda.Total(), estoque())
+	}
+	enviar(http.MethodPut, caminho, alteracao, http.StatusConflict, nil)
+
+	enviar(http.MethodPost, caminho+"/finalizar", map[string]any{"Pagamentos": []any{map[string]any{"Forma": "pix", "Valor": 10}}}, http.StatusUnprocessableEntity, nil)
+	enviar(http.MethodPost, caminho+"/finalizar", map[string]any{"Pagamentos": []any{map[string]any{"Forma": "pix"}}}, http.StatusOK, &venda)
+	if venda.GetStatus() != entidades.VendaFinalizada {
+		t.Errorf("venda %s, esperado finalizada", venda.GetStatus())
+	}
+	enviar(http.MethodDelete, caminho, nil, http.StatusConflict, nil)
+
+	enviar(http.MethodPost, caminho+"/cancelar", map[string]any{"Motivo": " "}, http.StatusBadRequest, nil)
+	enviar(http.MethodPost, caminho+"/cancelar", map[string]any{"Motivo": "desistência"}, http.StatusOK, &venda)
+	if venda.GetStatus() != entidades.VendaCancelada || estoque() != 10 {
+		t.Errorf("venda %s com estoque %d, esperado cancelada e 10", venda.GetStatus(), estoque())
+	}
+
+	enviar(http.MethodPost, "/vendas", map[string]any{"Itens": []any{map[string]any{"ProdutoID": produtoID, "Quantidade": 1}}}, http.StatusCreated, &venda)
+	enviar(http.MethodDelete, fmt.Sprintf("/vendas/%d", venda.GetID()), nil, http.StatusNoContent, nil)
+	enviar(http.MethodGet, fmt.Sprintf("/vendas/%d", venda.GetID()), nil, http.StatusNotFound, nil)
+	if estoque() != 10 {
+		t.Errorf("estoque %d após descartar a venda, esperado 10", estoque())
+	}
+
+	enviar(http.MethodPost, "/vendas", map[string]any{"Itens": []any{map[string]any{"ProdutoID": produtoID, "Quantidade": 11}}}, http.StatusUnprocessableEntity, nil)
+	enviar(http.MethodPost, "/vendas", map[string]any{"Itens": []any{map[string]any{"ProdutoID": 999999, "Quantidade": 1}}}, http.StatusBadRequest, nil)
+}
+
+func TestListarVendasFiltradasEPaginadas(t *testing.T) {
+	servidor := NewServidor()
+	produtoID := cadastrarProduto(t, servidor, "Régua de listagem", 5, 100)
+
+	abertas := map[int64]bool{}
+	for i := 0; i < 5; i++ {
+		var venda entidades.Venda
+		corpo := map[string]any{"Itens": []any{map[string]any{"ProdutoID": produtoID, "Quantidade": 1}}}
+		if i%2 == 0 {
+			corpo["Pagamentos"] = []any{map[string]any{"Forma": "dinheiro"}}
+		}
+		esperar(t, requisitar(t, servidor, http.MethodPost, "/vendas", corpo), http.StatusCreated, &venda)
+		if venda.Aberta() {
+			abertas[venda.GetID()] = true
+		}
+	}
+	if len(abertas) != 2 {
+		t.Fatalf("%d vendas abertas, esperado 2", len(abertas))
+	}
+
+	vistas := map[int64]bool{}
+	cursor, total := "", 0
+	for {
+		parametros := url.Values{"status": {"ABERTA"}, "limite": {"1"}, "cursor": {cursor}}
+		var pagina Pagina[*entidades.Venda]
+		esperar(t, requisitar(t, servidor, http.MethodGet, "/vendas?"+parametros.Encode(), nil), http.StatusOK, &pagina)
+		total = pagina.Total
+		for _, v := range pagina.Itens {
+			if !v.Aberta() || vistas[v.GetID()] {
+				t.Fatalf("venda %d %s repetida ou fora do filtro", v.GetID(), v.GetStatus())
+			}
+			vistas[v.GetID()] = true
+		}
+		if pagina.ProximoCursor == "" {
+			break
+		}
+		cursor = pagina.ProximoCursor
+	}
+	if len(vistas) != total {
+		t.Errorf("%d vendas percorridas, esperado o total de %d", len(vistas), total)
+	}
+	for id := range abertas {
+		if !vistas[id] {
+			t.Errorf("venda aberta %d fora da listagem", id)
+		}
+	}
+
+	var pagina Pagina[*entidades.Venda]
+	esperar(t, requisitar(t, servidor, http.MethodGet, "/vendas?status=finalizada&de=2000-01-01&ate=2000-12-31", nil), http.StatusOK, &pagina)
+	if pagina.Total != 0 || pagina.Itens == nil {
+		t.Errorf("vendas de 2000 = %+v, esperado uma lista vazia", pagina)
+	}
+}
+
+func TestVendaComPagamentoInsuficienteNaoERegistrada(t *testing.T) {
+	servidor := NewServidor()
+	produtoID := cadastrarProduto(t, servidor, "Estojo sem pagamento", 30, 5)
+	vendas := servidor.vendas.Consultar().Contar()
+
+	corpo := map[string]any{
+		"Itens":      []any{map[string]any{"ProdutoID": produtoID, "Quantidade": 2}},
+		"Pagamentos": []any{map[string]any{"Forma": "dinheiro", "Valor": 10}},
+	}
+	resposta := requisitar(t, servidor, http.MethodPost, "/vendas", corpo)
+	esperar(t, resposta, http.StatusUnprocessableEntity, nil)
+	if local := resposta.Header().Get("Location"); local != "" {
+		t.Errorf("Location = %q para uma venda não registrada", local)
+	}
+
+	if depois := servidor.vendas.Consultar().Contar(); depois != vendas {
+		t.Errorf("%d vendas após a recusa, esperado %d", depois, vendas)
+	}
+	produto, err := servidor.produtos.Buscar(produtoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if produto.GetEstoque() != 5 {
+		t.Errorf("estoque %d após a recusa, esperado 5", produto.GetEstoque())
+	}
+}
+
+func TestAlterarVendaSemClienteRemoveCliente(t *testing.T) {
+	servidor := NewServidor()
+	produtoID := cadastrarProduto(t, servidor, "Pasta com cliente", 12, 5)
+	cliente := entidades.NewCliente("Maria", "529.982.247-25")
+	if err := data.GetClienteInstance().Adicionar(cliente); err != nil {
+		t.Fatal(err)
+	}
+
+	itens := []any{map[string]any{"ProdutoID": produtoID, "Quantidade": 1}}
+	var venda entidades.Venda
+	esperar(t, requisitar(t, servidor, http.MethodPost, "/vendas", map[string]any{"Itens": itens, "Cliente": "52998224725"}), http.StatusCreated, &venda)
+	if venda.ClienteID != cliente.GetID() {
+		t.Fatalf("venda com cliente %d, esperado %d", venda.ClienteID, cliente.GetID())
+	}
+
+	caminho := fmt.Sprintf("/vendas/%d", venda.GetID())
+	var alterada entidades.Venda
+	esperar(t, requisitar(t, servidor, http.MethodPut, caminho, map[string]any{"Itens": itens}), http.StatusOK, &alterada)
+	if alterada.ClienteID != 0 || alterada.NomeCliente != "" {
+		t.Errorf("venda com cliente %d (%q) após a alteração sem cliente, esperado nenhum", alterada.ClienteID, alterada.NomeCliente)
+	}
+}
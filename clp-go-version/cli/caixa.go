@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// caixaAbrir implementa "clp caixa abrir": abre o caixa, necessário para registrar vendas.
+func caixaAbrir(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("caixa abrir", erro)
+	operador := opcoes.String("operador", "", "operador do caixa (obrigatório)")
+	fundo := opcoes.String("fundo", "0", "fundo de troco em dinheiro, como 100 ou 100,00")
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+	if strings.TrimSpace(*operador) == "" {
+		return fmt.Errorf("%w: informe --operador", ErrUso)
+	}
+	fundoTroco, err := entidades.ParseDinheiro(*fundo, entidades.MoedaPadrao)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUso, err)
+	}
+
+	caixa, err := data.GetCaixaInstance().Abrir(*operador, fundoTroco)
+	if err != nil {
+		return err
+	}
+	if formatoSaida == "json" {
+		return escreverJSON(saida, caixa)
+	}
+	_, err = fmt.Fprint(saida, caixa.String())
+	return err
+}
+
+// caixaStatus implementa "clp caixa status": exibe o caixa aberto com as suas sangrias e
+// suprimentos. Assim como no menu, os valores esperados não são exibidos, para não
+// comprometer a contagem cega do fechamento.
+func caixaStatus(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("caixa status", erro)
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+
+	caixa, err := data.GetCaixaInstance().Atual()
+	if err != nil {
+		return err
+	}
+	if formatoSaida == "json" {
+		return escreverJSON(saida, caixa)
+	}
+	_, err = fmt.Fprint(saida, caixa.String())
+	return err
+}
+
+// caixaFechar implementa "clp caixa fechar": fecha o caixa aberto com os valores contados
+// em cada forma de pagamento, considerando zero as formas não informadas, e exibe a
+// conferência com os valores esperados.
+func caixaFechar(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("caixa fechar", erro)
+	var contagens lista
+	opcoes.Var(&contagens, "contado", "valor contado no formato \"forma:valor\", como \"dinheiro:150,00\" (repetível)")
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+
+	contado := map[entidades.FormaPagamento]entidades.Dinheiro{}
+	for _, forma := range entidades.FormasPagamento {
+		contado[forma] = entidades.Reais(0)
+	}
+	for _, texto := range contagens {
+		nome, valor, ok := strings.Cut(texto, ":")
+		if !ok {
+			return fmt.Errorf("%w: contagem inválida %q (use forma:valor)", ErrUso, texto)
+		}
+		forma, err := entidades.ParseFormaPagamento(nome)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUso, err)
+		}
+		if contado[forma], err = entidades.ParseDinheiro(valor, entidades.MoedaPadrao); err != nil {
+			return fmt.Errorf("%w: %v", ErrUso, err)
+		}
+	}
+
+	caixa, err := data.GetCaixaInstance().Fechar(contado)
+	if err != nil {
+		return err
+	}
+	if formatoSaida == "json" {
+		return escreverJSON(saida, caixa)
+	}
+	_, err = fmt.Fprintf(saida, "Caixa %d fechado.\n%s", caixa.GetID(), caixa.Conferencia())
+	return err
+}
@@ -0,0 +1,195 @@
+// Package cli implementa os subcomandos não interativos do clp, como
+//
+//	clp produto add --nome Arroz --valor 9.90
+//	clp produto list --format json
+//	clp produto import produtos.csv --dry-run
+//	clp caixa abrir --operador maria --fundo 100
+//	clp venda add --item "Arroz:2" --pagamento pix
+//	clp venda show 1
+//	clp caixa fechar --contado dinheiro:150 --contado pix:19,80
+//	clp serve --addr :8080
+//
+// Os subcomandos usam os mesmos DAOs e arquivos de dados do menu interativo, que continua
+// sendo executado quando o clp é chamado sem argumentos.
+package cli
+
+import (
+	"clp-go-version/data"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Códigos de saída dos subcomandos.
+const (
+	SaidaSucesso       = 0 // Comando executado.
+	SaidaErro          = 1 // O comando falhou, como em uma venda sem estoque.
+	SaidaUso           = 2 // Subcomando ou opções inválidos.
+	SaidaNaoEncontrado = 3 // O produto, a venda ou o cliente informado não existe.
+)
+
+// ErrUso indica um subcomando ou opção inválidos; resulta no código SaidaUso.
+var ErrUso = errors.New("uso inválido")
+
+// comando é um subcomando, que recebe os argumentos seguintes ao seu nome.
+type comando struct {
+	nome      string
+	descricao string
+	executar  func(args []string, saida, erro io.Writer) error
+}
+
+// comandos lista os subcomandos, agrupados pela entidade.
+var comandos = map[string][]comando{
+	"produto": {
+		{"add", "adiciona um produto (--nome, --valor, --sku, --ncm, --cfop, --categoria, --estoque)", produtoAdd},
+		{"list", "lista os produtos (--format text|json)", produtoList},
+		{"import", "importa produtos de um CSV (<arquivo> [--dry-run] [--separador] [--decimal ,|.] [--coluna \"Cabeçalho=campo\"...])", produtoImport},
+		{"export", "exporta os produtos em CSV ([arquivo] [--separador ,|;|tab] [--decimal ,|.])", produtoExport},
+		{"analise", "curva ABC, estoque parado e sugestão de compra ([--dias 90] [--parado 30] [--cobertura 15] [--csv abc|classes|parados|compra])", produtoAnalise},
+	},
+	"venda": {
+		{"add", "registra uma venda (--item \"Nome:qtd\"..., --cliente, --pagamento \"forma[:valor[:parcelas]]\"...)", vendaAdd},
+		{"show", "exibe uma venda (<id> [--format text|json])", vendaShow},
+		{"finalizar", "finaliza uma venda aberta (<id> --pagamento \"forma[:valor[:parcelas]]\"...)", vendaFinalizar},
+		{"cancelar", "cancela uma venda e devolve os itens ao estoque (<id> --motivo)", vendaCancelar},
+	},
+	"caixa": {
+		{"abrir", "abre o caixa, necessário para as vendas (--operador [--fundo 0])", caixaAbrir},
+		{"status", "exibe o caixa aberto, com sangrias e suprimentos", caixaStatus},
+		{"fechar", "fecha o caixa com a contagem cega ([--contado \"forma:valor\"...])", caixaFechar},
+	},
+}
+
+// comandosGerais lista os subcomandos que não pertencem a uma entidade.
+var comandosGerais = []comando{
+	{"serve", "inicia a API HTTP de produtos e vendas ([--addr :8080])", serve},
+}
+
+// Executar executa o subcomando indicado por args (sem o nome do programa), escrevendo o
+// resultado em saida e as mensagens de erro em erro. Retorna o código de saída do processo.
+func Executar(args []string, saida, erro io.Writer) int {
+	if len(args) == 0 || args[0] == "ajuda" || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		uso(saida)
+		return SaidaSucesso
+	}
+	for _, c := range comandosGerais {
+		if c.nome == args[0] {
+			return codigoSaida(c.executar(args[1:], saida, erro), erro)
+		}
+	}
+	if len(args) < 2 {
+		fmt.Fprintf(erro, "clp: informe o subcomando de %s\n", args[0])
+		uso(erro)
+		return SaidaUso
+	}
+	for _, c := range comandos[args[0]] {
+		if c.nome == args[1] {
+			return codigoSaida(c.executar(args[2:], saida, erro), erro)
+		}
+	}
+	fmt.Fprintf(erro, "clp: subcomando desconhecido: %s\n", strings.Join(args[:2], " "))
+	uso(erro)
+	return SaidaUso
+}
+
+// uso exibe os subcomandos disponíveis.
+func uso(w io.Writer) {
+	fmt.Fprintln(w, "Uso: clp [<entidade> <subcomando> [opções]] | clp serve [opções]")
+	fmt.Fprintln(w, "Sem argumentos, abre o menu interativo.")
+	for _, entidade := range []string{"produto", "caixa", "venda"} {
+		for _, c := range comandos[entidade] {
+			fmt.Fprintf(w, "  clp %-7s %-9s %s\n", entidade, c.nome, c.descricao)
+		}
+	}
+	for _, c := range comandosGerais {
+		fmt.Fprintf(w, "  clp %-17s %s\n", c.nome, c.descricao)
+	}
+}
+
+// codigoSaida exibe o erro, se houver, e retorna o código de saída correspondente.
+func codigoSaida(err error, w io.Writer) int {
+	switch {
+	case err == nil:
+		return SaidaSucesso
+	case errors.Is(err, flag.ErrHelp):
+		return SaidaSucesso
+	case errors.Is(err, ErrUso):
+		fmt.Fprintln(w, "clp:", err)
+		return SaidaUso
+	case errors.Is(err, data.ErrNaoEncontrado):
+		fmt.Fprintln(w, "clp:", err)
+		return SaidaNaoEncontrado
+	default:
+		fmt.Fprintln(w, "clp:", err)
+		return SaidaErro
+	}
+}
+
+// novasOpcoes cria o conjunto de opções de um subcomando. Os erros de análise são
+// retornados por analisar, e a ajuda das opções é escrita em erro.
+func novasOpcoes(nome string, erro io.Writer) *flag.FlagSet {
+	opcoes := flag.NewFlagSet("clp "+nome, flag.ContinueOnError)
+	opcoes.SetOutput(erro)
+	return opcoes
+}
+
+// analisar interpreta as opções de args, que podem vir antes ou depois dos argumentos
+// posicionais, e retorna os argumentos posicionais.
+func analisar(opcoes *flag.FlagSet, args []string) ([]string, error) {
+	var posicionais []string
+	for {
+		if err := opcoes.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", ErrUso, err)
+		}
+		args = opcoes.Args()
+		if len(args) == 0 {
+			return posicionais, nil
+		}
+		posicionais = append(posicionais, args[0])
+		args = args[1:]
+	}
+}
+
+// lista é uma opção que pode ser informada várias vezes, como --item.
+type lista []string
+
+// String implementa a interface flag.Value.
+func (l *lista) String() string {
+	return strings.Join(*l, ", ")
+}
+
+// Set implementa a interface flag.Value, acrescentando o valor à lista.
+func (l *lista) Set(valor string) error {
+	*l = append(*l, valor)
+	return nil
+}
+
+// formato é o valor da opção --format: "text" ou "json".
+type formato string
+
+// String implementa a interface flag.Value.
+func (f *formato) String() string {
+	return string(*f)
+}
+
+// Set implementa a interface flag.Value, aceitando apenas os formatos conhecidos.
+func (f *formato) Set(valor string) error {
+	if valor != "text" && valor != "json" {
+		return fmt.Errorf("formato desconhecido %q (use text ou json)", valor)
+	}
+	*f = formato(valor)
+	return nil
+}
+
+// escreverJSON escreve valor em saida como JSON indentado.
+func escreverJSON(saida io.Writer, valor any) error {
+	codificador := json.NewEncoder(saida)
+	codificador.SetIndent("", "  ")
+	return codificador.Encode(valor)
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"clp-go-version/relatorios"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// produtoAdd implementa "clp produto add": cadastra um produto e, com --estoque,
+// registra o estoque inicial como entrada, assim como o menu de produtos.
+func produtoAdd(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("produto add", erro)
+	nome := opcoes.String("nome", "", "nome do produto (obrigatório)")
+	valor := opcoes.String("valor", "", "valor unitário, como 9.90 ou 9,90 (obrigatório)")
+	sku := opcoes.String("sku", "", "código do produto")
+	ncm := opcoes.String("ncm", "", "classificação NCM (8 dígitos)")
+	cfop := opcoes.String("cfop", "", "CFOP (4 dígitos; 5933 para serviços)")
+	categoria := opcoes.String("categoria", "", "categoria do produto")
+	estoque := opcoes.Int("estoque", 0, "estoque inicial")
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+
+	if strings.TrimSpace(*nome) == "" || *valor == "" {
+		return fmt.Errorf("%w: informe --nome e --valor", ErrUso)
+	}
+	if *estoque < 0 {
+		return fmt.Errorf("%w: o estoque inicial não pode ser negativo", ErrUso)
+	}
+	preco, err := entidades.ParseDinheiro(*valor, entidades.MoedaPadrao)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUso, err)
+	}
+
+	produto := entidades.NewProduto(strings.TrimSpace(*nome), preco)
+	produto.SetSKU(*sku)
+	produto.SetNCM(*ncm)
+	produto.SetCFOP(*cfop)
+	produto.SetCategoria(*categoria)
+	if err := data.GetInstance().AdicionarComEstoque(produto, *estoque, "estoque inicial"); err != nil {
+		return err
+	}
+	if produto, err = data.GetInstance().Buscar(produto.GetID()); err != nil {
+		return err
+	}
+
+	if formatoSaida == "json" {
+		return escreverJSON(saida, produto)
+	}
+	_, err = fmt.Fprintln(saida, produto.String())
+	return err
+}
+
+// produtoList implementa "clp produto list": lista os produtos em ordem de ID.
+func produtoList(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("produto list", erro)
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+
+	produtos := data.GetInstance().Consultar().Listar()
+	if formatoSaida == "json" {
+		if produtos == nil {
+			produtos = []*entidades.Produto{}
+		}
+		return escreverJSON(saida, produtos)
+	}
+	for _, p := range produtos {
+		if _, err := fmt.Fprintln(saida, p.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// produtoImport implementa "clp produto import <arquivo>": adiciona e atualiza produtos a
+// partir de um arquivo CSV (ver data.ImportarProdutosCSV). Com --dry-run, apenas valida as
+// linhas. Se alguma linha for recusada, o comando termina com erro.
+func produtoImport(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("produto import", erro)
+	simular := opcoes.Bool("dry-run", false, "apenas valida o arquivo, sem gravar os produtos")
+	var separador separadorCSV
+	opcoes.Var(&separador, "separador", "separador de colunas: \";\", \",\" ou \"tab\" (padrão: detectado pelo cabeçalho)")
+	var decimal separadorDecimal
+	opcoes.Var(&decimal, "decimal", "separador decimal dos valores: \",\" ou \".\" (padrão: \",\" com o separador \";\" e \".\" nos demais)")
+	var colunas lista
+	opcoes.Var(&colunas, "coluna", "associa um cabeçalho do arquivo a um campo, como \"Descrição Curta=nome\" (repetível)")
+	posicionais, err := analisar(opcoes, args)
+	if err != nil {
+		return err
+	}
+	if len(posicionais) != 1 {
+		return fmt.Errorf("%w: informe o arquivo CSV", ErrUso)
+	}
+
+	opcoesCSV := data.OpcoesCSV{Separador: rune(separador), Decimal: rune(decimal), Colunas: map[string]string{}, Simular: *simular}
+	for _, coluna := range colunas {
+		titulo, campo, ok := strings.Cut(coluna, "=")
+		if !ok || strings.TrimSpace(titulo) == "" {
+			return fmt.Errorf("%w: coluna inválida %q (use \"Cabeçalho=campo\")", ErrUso, coluna)
+		}
+		opcoesCSV.Colunas[titulo] = campo
+	}
+
+	arquivo, err := os.Open(posicionais[0])
+	if err != nil {
+		return err
+	}
+	defer arquivo.Close()
+	resultado, err := data.ImportarProdutosCSV(arquivo, opcoesCSV)
+	if err != nil {
+		return fmt.Errorf("%s: %w", posicionais[0], err)
+	}
+	if _, err := fmt.Fprint(saida, resultado.String()); err != nil {
+		return err
+	}
+	if len(resultado.Erros) > 0 {
+		return fmt.Errorf("%d linha(s) recusada(s)", len(resultado.Erros))
+	}
+	return nil
+}
+
+// produtoExport implementa "clp produto export [arquivo]": grava os produtos em CSV no
+// arquivo informado ou, sem ele, na saída padrão.
+func produtoExport(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("produto export", erro)
+	separador := separadorCSV(',')
+	opcoes.Var(&separador, "separador", "separador de colunas: \",\", \";\" (com vírgula decimal) ou \"tab\"")
+	var decimal separadorDecimal
+	opcoes.Var(&decimal, "decimal", "separador decimal dos valores: \",\" ou \".\" (padrão: \",\" com o separador \";\" e \".\" nos demais)")
+	posicionais, err := analisar(opcoes, args)
+	if err != nil {
+		return err
+	}
+	if len(posicionais) > 1 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[1])
+	}
+
+	opcoesCSV := data.OpcoesCSV{Separador: rune(separador), Decimal: rune(decimal)}
+	if len(posicionais) == 0 {
+		return data.ExportarProdutosCSV(saida, opcoesCSV)
+	}
+	arquivo, err := os.Create(posicionais[0])
+	if err != nil {
+		return err
+	}
+	err = data.ExportarProdutosCSV(arquivo, opcoesCSV)
+	if errFechar := arquivo.Close(); err == nil {
+		err = errFechar
+	}
+	return err
+}
+
+// separadorCSV é o valor da opção --separador.
+type separadorCSV rune
+
+// String implementa a interface flag.Value.
+func (s *separadorCSV) String() string {
+	if *s == 0 {
+		return ""
+	}
+	if *s == '\t' {
+		return "tab"
+	}
+	return string(rune(*s))
+}
+
+// Set implementa a interface flag.Value, aceitando ",", ";" e "tab".
+func (s *separadorCSV) Set(valor string) error {
+	switch valor {
+	case ",", ";":
+		*s = separadorCSV(valor[0])
+	case "tab", "\t":
+		*s = '\t'
+	default:
+		return fmt.Errorf("separador desconhecido %q (use \",\", \";\" ou \"tab\")", valor)
+	}
+	return nil
+}
+
+// separadorDecimal é o valor da opção --decimal.
+type separadorDecimal rune
+
+// String implementa a interface flag.Value.
+func (s *separadorDecimal) String() string {
+	if *s == 0 {
+		return ""
+	}
+	return string(rune(*s))
+}
+
+// Set implementa a interface flag.Value, aceitando "," e ".".
+func (s *separadorDecimal) Set(valor string) error {
+	if valor != "," && valor != "." {
+		return fmt.Errorf("separador decimal desconhecido %q (use \",\" ou \".\")", valor)
+	}
+	*s = separadorDecimal(valor[0])
+	return nil
+}
+
+// tabelasAnalise relaciona os valores de --csv de "clp produto analise" à posição da tabela
+// em relatorios.Analise.Tabelas.
+var tabelasAnalise = map[string]int{"classes": 0, "abc": 1, "parados": 2, "compra": 3}
+
+// produtoAnalise implementa "clp produto analise": exibe a curva ABC dos produtos, o estoque
+// parado e as sugestões de compra (ver relatorios.Analisar) ou, com --csv, uma dessas
+// tabelas em formato CSV.
+func produtoAnalise(args []string, saida, erro io.Writer) error {
+	padrao := relatorios.OpcoesAnalisePadrao(time.Now())
+	opcoes := novasOpcoes("produto analise", erro)
+	dias := opcoes.Int("dias", 90, "dias de vendas considerados, até hoje")
+	parado := opcoes.Int("parado", padrao.DiasParado, "dias sem venda a partir dos quais o estoque é considerado parado")
+	cobertura := opcoes.Int("cobertura", padrao.DiasCobertura, "dias de venda que o estoque deve cobrir")
+	tabela := opcoes.String("csv", "", "grava a tabela informada em CSV: classes, abc, parados ou compra")
+	separador := separadorCSV(',')
+	opcoes.Var(&separador, "separador", "separador de colunas do CSV: \",\", \";\" (com vírgula decimal) ou \"tab\"")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+	if *dias <= 0 || *parado <= 0 || *cobertura <= 0 {
+		return fmt.Errorf("%w: --dias, --parado e --cobertura devem ser positivos", ErrUso)
+	}
+	indice, ok := tabelasAnalise[*tabela]
+	if *tabela != "" && !ok {
+		return fmt.Errorf("%w: tabela desconhecida %q (use classes, abc, parados ou compra)", ErrUso, *tabela)
+	}
+
+	agora := time.Now()
+	analise := relatorios.Analisar(relatorios.OpcoesAnalise{
+		Periodo:       relatorios.EntreDatas(agora.AddDate(0, 0, 1-*dias), agora),
+		LimiteA:       padrao.LimiteA,
+		LimiteB:       padrao.LimiteB,
+		DiasParado:    *parado,
+		DiasCobertura: *cobertura,
+	})
+	tabelas := analise.Tabelas()
+	if *tabela != "" {
+		return tabelas[indice].EscreverCSV(saida, rune(separador))
+	}
+	for _, t := range tabelas {
+		if _, err := fmt.Fprintln(saida, t.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"clp-go-version/api"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// serve implementa "clp serve": atende a API HTTP de produtos e vendas (ver o pacote api)
+// até o processo receber SIGINT ou SIGTERM, quando aguarda as requisições em andamento.
+func serve(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("serve", erro)
+	endereco := opcoes.String("addr", ":8080", "endereço em que a API é atendida")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+
+	ouvinte, err := net.Listen("tcp", *endereco)
+	if err != nil {
+		return err
+	}
+	servidor := &http.Server{
+		Handler:           api.NewServidor(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, parar := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer parar()
+	encerrado := make(chan struct{})
+	go func() {
+		defer close(encerrado)
+		<-ctx.Done()
+		encerrar, cancelar := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancelar()
+		servidor.Shutdown(encerrar)
+	}()
+
+	fmt.Fprintf(saida, "API atendendo em http://%s\n", ouvinte.Addr())
+	if err := servidor.Serve(ouvinte); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-encerrado
+	fmt.Fprintln(saida, "API encerrada.")
+	return nil
+}
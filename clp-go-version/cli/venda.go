@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// vendaAdd implementa "clp venda add": registra uma venda com os itens informados e,
+// se houver --pagamento, finaliza-a. Assim como no menu, a venda exige um caixa aberto.
+func vendaAdd(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("venda add", erro)
+	var itens, pagamentos lista
+	opcoes.Var(&itens, "item", "item da venda no formato \"Nome:quantidade\" ou \"SKU:quantidade\" (repetível)")
+	opcoes.Var(&pagamentos, "pagamento", "pagamento no formato \"forma[:valor[:parcelas]]\"; sem valor, paga o restante (repetível)")
+	documento := opcoes.String("cliente", "", "CPF ou CNPJ do cliente")
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	if posicionais, err := analisar(opcoes, args); err != nil {
+		return err
+	} else if len(posicionais) > 0 {
+		return fmt.Errorf("%w: argumento inesperado %q", ErrUso, posicionais[0])
+	}
+	if len(itens) == 0 {
+		return fmt.Errorf("%w: informe ao menos um --item", ErrUso)
+	}
+
+	venda := entidades.NewVenda()
+	if *documento != "" {
+		cliente, err := data.GetClienteInstance().BuscarPorDocumento(*documento)
+		if err != nil {
+			return err
+		}
+		venda.SetCliente(cliente)
+	}
+	for _, item := range itens {
+		produto, quantidade, err := lerItem(item)
+		if err != nil {
+			return err
+		}
+		venda.AdicionarItem(*produto, quantidade)
+	}
+
+	daoVenda := data.GetVendaInstance()
+	if len(pagamentos) > 0 {
+		// Os pagamentos são lidos sobre a venda com as promoções que ela receberá, para que
+		// um pagamento sem valor pague o restante correto.
+		lidos, err := lerPagamentos(daoVenda.Previa(venda), pagamentos)
+		if err != nil {
+			return err
+		}
+		if venda, err = daoVenda.AdicionarFinalizada(venda, lidos); err != nil {
+			return fmt.Errorf("venda não registrada: %w", err)
+		}
+	} else if err := daoVenda.Adicionar(venda); err != nil {
+		return err
+	}
+
+	if formatoSaida == "json" {
+		return escreverJSON(saida, venda)
+	}
+	_, err := fmt.Fprint(saida, venda.String())
+	return err
+}
+
+// vendaShow implementa "clp venda show <id>": exibe a venda com o ID informado.
+func vendaShow(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("venda show", erro)
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	posicionais, err := analisar(opcoes, args)
+	if err != nil {
+		return err
+	}
+	id, err := lerIDVenda(posicionais)
+	if err != nil {
+		return err
+	}
+
+	venda, err := data.GetVendaInstance().Buscar(id)
+	if err != nil {
+		return fmt.Errorf("venda %d: %w", id, err)
+	}
+	if formatoSaida == "json" {
+		return escreverJSON(saida, venda)
+	}
+	_, err = fmt.Fprint(saida, venda.String())
+	return err
+}
+
+// vendaFinalizar implementa "clp venda finalizar <id>": finaliza uma venda aberta com os
+// pagamentos informados.
+func vendaFinalizar(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("venda finalizar", erro)
+	var pagamentos lista
+	opcoes.Var(&pagamentos, "pagamento", "pagamento no formato \"forma[:valor[:parcelas]]\"; sem valor, paga o restante (repetível)")
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	posicionais, err := analisar(opcoes, args)
+	if err != nil {
+		return err
+	}
+	id, err := lerIDVenda(posicionais)
+	if err != nil {
+		return err
+	}
+	if len(pagamentos) == 0 {
+		return fmt.Errorf("%w: informe ao menos um --pagamento", ErrUso)
+	}
+
+	venda, err := finalizar(id, pagamentos)
+	if err != nil {
+		return err
+	}
+	if formatoSaida == "json" {
+		return escreverJSON(saida, venda)
+	}
+	_, err = fmt.Fprint(saida, venda.String())
+	return err
+}
+
+// vendaCancelar implementa "clp venda cancelar <id>": cancela uma venda aberta ou
+// finalizada, devolvendo os itens ao estoque, assim como o menu de vendas.
+func vendaCancelar(args []string, saida, erro io.Writer) error {
+	opcoes := novasOpcoes("venda cancelar", erro)
+	motivo := opcoes.String("motivo", "", "motivo do cancelamento (obrigatório)")
+	formatoSaida := formato("text")
+	opcoes.Var(&formatoSaida, "format", "formato da saída: text ou json")
+	posicionais, err := analisar(opcoes, args)
+	if err != nil {
+		return err
+	}
+	id, err := lerIDVenda(posicionais)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*motivo) == "" {
+		return fmt.Errorf("%w: informe --motivo", ErrUso)
+	}
+
+	venda, err := data.GetVendaInstance().Cancelar(id, strings.TrimSpace(*motivo))
+	if err != nil {
+		return fmt.Errorf("venda %d: %w", id, err)
+	}
+	if formatoSaida == "json" {
+		return escreverJSON(saida, venda)
+	}
+	_, err = fmt.Fprint(saida, venda.String())
+	return err
+}
+
+// lerIDVenda retorna o ID da venda, único argumento posicional dos subcomandos de venda.
+func lerIDVenda(posicionais []string) (int64, error) {
+	if len(posicionais) != 1 {
+		return 0, fmt.Errorf("%w: informe o ID da venda", ErrUso)
+	}
+	id, err := strconv.ParseInt(posicionais[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: ID de venda inválido: %q", ErrUso, posicionais[0])
+	}
+	return id, nil
+}
+
+// finalizar finaliza a venda aberta com os pagamentos no formato "forma[:valor[:parcelas]]".
+func finalizar(id int64, textos []string) (*entidades.Venda, error) {
+	daoVenda := data.GetVendaInstance()
+	venda, err := daoVenda.Buscar(id)
+	if err != nil {
+		return nil, fmt.Errorf("venda %d: %w", id, err)
+	}
+	pagamentos, err := lerPagamentos(venda.Clonar(), textos)
+	if err != nil {
+		return nil, err
+	}
+	return daoVenda.Finalizar(id, pagamentos)
+}
+
+// lerItem interpreta um item no formato "Nome:quantidade", em que o produto é buscado pelo
+// nome e, se não for encontrado, pelo SKU. Sem a quantidade, é vendida uma unidade.
+func lerItem(texto string) (*entidades.Produto, int, error) {
+	nome, quantidade := texto, 1
+	if i := strings.LastIndex(texto, ":"); i >= 0 {
+		n, err := strconv.Atoi(strings.TrimSpace(texto[i+1:]))
+		if err != nil || n <= 0 {
+			return nil, 0, fmt.Errorf("%w: quantidade inválida no item %q", ErrUso, texto)
+		}
+		nome, quantidade = texto[:i], n
+	}
+	daoProduto := data.GetInstance()
+	produto, err := daoProduto.BuscarPorNome(nome)
+	if errors.Is(err, data.ErrNaoEncontrado) {
+		if porSKU, errSKU := daoProduto.BuscarPorSKU(nome); errSKU == nil {
+			return porSKU, quantidade, nil
+		}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return produto, quantidade, nil
+}
+
+// lerPagamentos interpreta os pagamentos no formato "forma[:valor[:parcelas]]" e os aplica
+// à venda informada, na ordem, para validá-los. Um pagamento sem valor paga o restante.
+func lerPagamentos(venda *entidades.Venda, textos []string) ([]entidades.Pagamento, error) {
+	venda.RemoverPagamentos()
+	for _, texto := range textos {
+		partes := strings.Split(texto, ":")
+		if len(partes) > 3 {
+			return nil, fmt.Errorf("%w: pagamento inválido %q", ErrUso, texto)
+		}
+		forma, err := entidades.ParseFormaPagamento(partes[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUso, err)
+		}
+		valor := venda.Restante()
+		if len(partes) > 1 && partes[1] != "" {
+			if valor, err = entidades.ParseDinheiro(partes[1], entidades.MoedaPadrao); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrUso, err)
+			}
+		}
+		pagamento := entidades.NewPagamento(forma, valor)
+		if len(partes) > 2 {
+			parcelas, err := strconv.Atoi(partes[2])
+			if err != nil {
+				return nil, fmt.Errorf("%w: parcelas inválidas no pagamento %q", ErrUso, texto)
+			}
+			pagamento.Parcelas = parcelas
+		}
+		if err := venda.AdicionarPagamento(pagamento); err != nil {
+			return nil, fmt.Errorf("%w: %v", data.ErrInvalido, err)
+		}
+	}
+	return venda.Pagamentos, nil
+}
@@ -0,0 +1,237 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// ErrCursorInvalido indica um cursor de paginação malformado ou gerado por uma consulta
+// com outra ordenação.
+var ErrCursorInvalido = errors.New("cursor de paginação inválido")
+
+// Consulta monta uma leitura de um DAO com filtros, ordenação e paginação.
+// É criada por DAO.Consultar e configurada por encadeamento de métodos:
+//
+//	dao.Consultar().
+//		Onde(func(v *entidades.Venda) bool { return v.Total().Positivo() }).
+//		OrdenarPor(Decrescente(PorChave(func(v *entidades.Venda) int64 { return v.GetDataHora().UnixNano() }))).
+//		Limite(10).
+//		Listar()
+//
+// A consulta é executada sobre um retrato do DAO obtido no momento da execução.
+type Consulta[E entidades.Entidade] struct {
+	dao          *DAO[E]
+	filtros      []func(E) bool
+	ordens       []Ordem[E]
+	limite       int
+	deslocamento int
+	cursor       string
+}
+
+// Pagina é o resultado de uma consulta paginada por cursor.
+type Pagina[E entidades.Entidade] struct {
+	Itens         []E
+	ProximoCursor string // Vazio quando não há mais páginas.
+}
+
+// Consultar inicia uma consulta sobre as entidades do DAO.
+func (d *DAO[E]) Consultar() *Consulta[E] {
+	return &Consulta[E]{dao: d}
+}
+
+// Onde adiciona um filtro. Apenas as entidades aceitas por todos os filtros são retornadas.
+func (c *Consulta[E]) Onde(filtro func(E) bool) *Consulta[E] {
+	c.filtros = append(c.filtros, filtro)
+	return c
+}
+
+// OrdenarPor adiciona um critério de ordenação, criado por PorChave ou Decrescente.
+// Critérios adicionados depois servem de desempate para os anteriores; o desempate
+// final é sempre o ID, o que torna a ordem (e a paginação) determinística.
+func (c *Consulta[E]) OrdenarPor(ordem Ordem[E]) *Consulta[E] {
+	c.ordens = append(c.ordens, ordem)
+	return c
+}
+
+// Limite define a quantidade máxima de entidades retornadas. Zero significa sem limite.
+func (c *Consulta[E]) Limite(n int) *Consulta[E] {
+	c.limite = n
+	return c
+}
+
+// Deslocamento descarta as primeiras n entidades do resultado.
+func (c *Consulta[E]) Deslocamento(n int) *Consulta[E] {
+	c.deslocamento = n
+	return c
+}
+
+// Apos continua a consulta a partir do cursor retornado em Pagina.ProximoCursor.
+// O cursor guarda as chaves de ordenação e o ID da última entidade da página, e a
+// consulta continua na primeira entidade posterior a esses valores. Assim, ao contrário
+// do deslocamento, o cursor não pula nem repete entidades quando outras são adicionadas
+// ou removidas entre uma página e outra, inclusive quando a própria última entidade foi
+// removida ou alterada. O cursor só é válido para uma consulta com a mesma ordenação.
+func (c *Consulta[E]) Apos(cursor string) *Consulta[E] {
+	c.cursor = cursor
+	return c
+}
+
+// Listar executa a consulta e retorna as entidades encontradas.
+// Um cursor inválido resulta em uma lista vazia; use Paginar para obter o erro.
+func (c *Consulta[E]) Listar() []E {
+	pagina, _ := c.Paginar()
+	return pagina.Itens
+}
+
+// Contar retorna quantas entidades atendem aos filtros, ignorando paginação.
+func (c *Consulta[E]) Contar() int {
+	return len(c.filtrar())
+}
+
+// Paginar executa a consulta e retorna a página de entidades junto com o cursor
+// para a próxima página. Só há próximo cursor quando um limite foi definido.
+func (c *Consulta[E]) Paginar() (Pagina[E], error) {
+	resultado := c.filtrar()
+	slices.SortStableFunc(resultado, c.comparar)
+
+	if c.cursor != "" {
+		posicao, err := c.lerCursor(c.cursor)
+		if err != nil {
+			return Pagina[E]{}, err
+		}
+		i := slices.IndexFunc(resultado, func(e E) bool { return posicao(e) > 0 })
+		if i < 0 {
+			i = len(resultado)
+		}
+		resultado = resultado[i:]
+	}
+
+	resultado = resultado[min(c.deslocamento, len(resultado)):]
+
+	pagina := Pagina[E]{Itens: resultado}
+	if c.limite > 0 && len(resultado) > c.limite {
+		pagina.Itens = resultado[:c.limite]
+		pagina.ProximoCursor = c.gravarCursor(pagina.Itens[c.limite-1])
+	}
+	return pagina, nil
+}
+
+// gravarCursor codifica as chaves de ordenação e o ID da entidade, em JSON e base64.
+func (c *Consulta[E]) gravarCursor(e E) string {
+	valores := make([]any, 0, len(c.ordens)+1)
+	for _, ordem := range c.ordens {
+		valores = append(valores, ordem.chave(e))
+	}
+	valores = append(valores, e.GetID())
+	texto, _ := json.Marshal(valores)
+	return base64.RawURLEncoding.EncodeToString(texto)
+}
+
+// lerCursor decodifica um cursor gravado por gravarCursor e retorna uma função que
+// compara uma entidade com a posição do cursor no estilo de cmp.Compare: positiva para
+// as entidades que vêm depois dele.
+func (c *Consulta[E]) lerCursor(cursor string) (func(E) int, error) {
+	texto, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrCursorInvalido
+	}
+	var valores []json.RawMessage
+	if err := json.Unmarshal(texto, &valores); err != nil || len(valores) != len(c.ordens)+1 {
+		return nil, ErrCursorInvalido
+	}
+	comparacoes := make([]func(E) int, len(c.ordens))
+	for i, ordem := range c.ordens {
+		if comparacoes[i], err = ordem.lerChave(valores[i]); err != nil {
+			return nil, ErrCursorInvalido
+		}
+	}
+	var id int64
+	if err := json.Unmarshal(valores[len(c.ordens)], &id); err != nil {
+		return nil, ErrCursorInvalido
+	}
+	return func(e E) int {
+		for _, comparar := range comparacoes {
+			if r := comparar(e); r != 0 {
+				return r
+			}
+		}
+		return cmp.Compare(e.GetID(), id)
+	}, nil
+}
+
+// filtrar retorna as entidades do DAO aceitas por todos os filtros.
+func (c *Consulta[E]) filtrar() []E {
+	return slices.DeleteFunc(c.dao.GetDados(), func(e E) bool {
+		for _, filtro := range c.filtros {
+			if !filtro(e) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// comparar aplica os critérios de ordenação em sequência e, por fim, o ID.
+func (c *Consulta[E]) comparar(a, b E) int {
+	for _, ordem := range c.ordens {
+		if r := ordem.Comparar(a, b); r != 0 {
+			return r
+		}
+	}
+	return cmp.Compare(a.GetID(), b.GetID())
+}
+
+// Ordem é um critério de ordenação pelo valor de uma chave, criado por PorChave.
+// Além de comparar duas entidades, a ordem grava a chave no cursor de paginação e
+// compara entidades com a chave lida de um cursor.
+type Ordem[E any] struct {
+	comparar func(a, b E) int
+	chave    func(e E) any
+	lerChave func(texto json.RawMessage) (func(e E) int, error)
+}
+
+// Comparar compara duas entidades no estilo de cmp.Compare, o que permite usar a ordem
+// também com slices.SortFunc.
+func (o Ordem[E]) Comparar(a, b E) int {
+	return o.comparar(a, b)
+}
+
+// PorChave cria um critério de ordenação crescente pelo valor retornado por chave.
+func PorChave[E any, K cmp.Ordered](chave func(E) K) Ordem[E] {
+	return Ordem[E]{
+		comparar: func(a, b E) int {
+			return cmp.Compare(chave(a), chave(b))
+		},
+		chave: func(e E) any {
+			return chave(e)
+		},
+		lerChave: func(texto json.RawMessage) (func(e E) int, error) {
+			var k K
+			if err := json.Unmarshal(texto, &k); err != nil {
+				return nil, err
+			}
+			return func(e E) int { return cmp.Compare(chave(e), k) }, nil
+		},
+	}
+}
+
+// Decrescente inverte um critério de ordenação.
+func Decrescente[E any](ordem Ordem[E]) Ordem[E] {
+	return Ordem[E]{
+		comparar: func(a, b E) int {
+			return ordem.comparar(b, a)
+		},
+		chave: ordem.chave,
+		lerChave: func(texto json.RawMessage) (func(e E) int, error) {
+			comparar, err := ordem.lerChave(texto)
+			if err != nil {
+				return nil, err
+			}
+			return func(e E) int { return -comparar(e) }, nil
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// novosProdutos cria um DAO em memória com produtos dos valores informados, em centavos.
+func novosProdutos(t *testing.T, valores ...int64) *DAO[*entidades.Produto] {
+	t.Helper()
+	dao := NewDAO[*entidades.Produto]()
+	for _, valor := range valores {
+		if err := dao.Adicionar(entidades.NewProduto("Produto", entidades.Reais(valor))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dao
+}
+
+// ids retorna os IDs das entidades, na ordem em que aparecem.
+func ids[E entidades.Entidade](lista []E) []int64 {
+	resultado := make([]int64, len(lista))
+	for i, e := range lista {
+		resultado[i] = e.GetID()
+	}
+	return resultado
+}
+
+func TestPaginarContinuaAposUltimoRemovidoOuAlterado(t *testing.T) {
+	// IDs 1 a 6 com valores 50, 10, 40, 20, 60, 30: em ordem de valor, 2 4 6 3 1 5.
+	dao := novosProdutos(t, 50, 10, 40, 20, 60, 30)
+	consultar := func(cursor string) Pagina[*entidades.Produto] {
+		t.Helper()
+		pagina, err := dao.Consultar().
+			OrdenarPor(PorChave(func(p *entidades.Produto) int64 { return p.GetValor().Centavos })).
+			Limite(2).
+			Apos(cursor).
+			Paginar()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pagina
+	}
+
+	primeira := consultar("")
+	if !slices.Equal(ids(primeira.Itens), []int64{2, 4}) {
+		t.Fatalf("primeira página = %v, esperado [2 4]", ids(primeira.Itens))
+	}
+	// O último item da página é removido antes da leitura da próxima.
+	if err := dao.Remover(4); err != nil {
+		t.Fatal(err)
+	}
+	segunda := consultar(primeira.ProximoCursor)
+	if !slices.Equal(ids(segunda.Itens), []int64{6, 3}) {
+		t.Fatalf("segunda página = %v, esperado [6 3]", ids(segunda.Itens))
+	}
+
+	// O último item da página passa a ter um valor maior, mas a leitura continua da
+	// posição em que ele estava.
+	produto, _ := dao.Buscar(3)
+	alterado := produto.Clonar()
+	alterado.SetValor(entidades.Reais(100))
+	if err := dao.Atualizar(alterado); err != nil {
+		t.Fatal(err)
+	}
+	terceira := consultar(segunda.ProximoCursor)
+	if !slices.Equal(ids(terceira.Itens), []int64{1, 5}) {
+		t.Fatalf("terceira página = %v, esperado [1 5]", ids(terceira.Itens))
+	}
+}
+
+func TestPaginarDecrescente(t *testing.T) {
+	dao := novosProdutos(t, 10, 30, 20, 30)
+	consulta := dao.Consultar().
+		OrdenarPor(Decrescente(PorChave(func(p *entidades.Produto) int64 { return p.GetValor().Centavos }))).
+		Limite(2)
+	var lidos []int64
+	for {
+		pagina, err := consulta.Paginar()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lidos = append(lidos, ids(pagina.Itens)...)
+		if pagina.ProximoCursor == "" {
+			break
+		}
+		consulta.Apos(pagina.ProximoCursor)
+	}
+	if !slices.Equal(lidos, []int64{2, 4, 3, 1}) {
+		t.Fatalf("ordem = %v, esperado [2 4 3 1]", lidos)
+	}
+}
+
+func TestPaginarCursorInvalido(t *testing.T) {
+	dao := novosProdutos(t, 10, 20, 30)
+	porValor := dao.Consultar().
+		OrdenarPor(PorChave(func(p *entidades.Produto) int64 { return p.GetValor().Centavos })).
+		Limite(1)
+	pagina, err := porValor.Paginar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cursor := range []string{"???", "bm9wZQ", pagina.ProximoCursor} {
+		// O cursor da ordenação por valor não vale para a ordenação apenas por ID.
+		if _, err := dao.Consultar().Limite(1).Apos(cursor).Paginar(); !errors.Is(err, ErrCursorInvalido) {
+			t.Errorf("Apos(%q): erro = %v, esperado ErrCursorInvalido", cursor, err)
+		}
+	}
+}
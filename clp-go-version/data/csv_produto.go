@@ -0,0 +1,445 @@
+package data
+
+import (
+	"bytes"
+	"clp-go-version/entidades"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Campos de produto lidos e gravados nos arquivos CSV.
+const (
+	campoNome      = "nome"
+	campoValor     = "valor"
+	campoSKU       = "sku"
+	campoNCM       = "ncm"
+	campoCFOP      = "cfop"
+	campoCategoria = "categoria"
+	campoEstoque   = "estoque"
+)
+
+// camposCSV são os campos na ordem em que são exportados.
+var camposCSV = []string{campoNome, campoValor, campoSKU, campoNCM, campoCFOP, campoCategoria, campoEstoque}
+
+// cabecalhosCSV relaciona os cabeçalhos reconhecidos na importação, normalizados (ver
+// NormalizarTexto), ao campo correspondente.
+var cabecalhosCSV = map[string]string{
+	"nome": campoNome, "produto": campoNome, "descricao": campoNome, "name": campoNome,
+	"valor": campoValor, "preco": campoValor, "preco de venda": campoValor, "valor unitario": campoValor, "price": campoValor,
+	"sku": campoSKU, "codigo": campoSKU, "cod": campoSKU, "code": campoSKU,
+	"ncm":       campoNCM,
+	"cfop":      campoCFOP,
+	"categoria": campoCategoria, "grupo": campoCategoria, "category": campoCategoria,
+	"estoque": campoEstoque, "quantidade": campoEstoque, "qtd": campoEstoque, "saldo": campoEstoque, "stock": campoEstoque,
+}
+
+// OpcoesCSV configura a importação e a exportação de produtos em CSV.
+type OpcoesCSV struct {
+	// Separador de colunas. Com zero, a importação o detecta pelo cabeçalho (';' ou ',')
+	// e a exportação usa ','.
+	Separador rune
+
+	// Separador decimal dos valores: ',' ou '.'. Com zero, é a vírgula quando o separador
+	// de colunas é ';', como nas planilhas em português, e o ponto nos demais casos. Na
+	// importação, o outro caractere só é aceito como separador de milhar, e valores que
+	// não se encaixam, como "1,234" com ponto decimal, são recusados em vez de adivinhados.
+	Decimal rune
+
+	// Colunas relaciona cabeçalhos do arquivo não reconhecidos automaticamente ao campo
+	// do produto ("nome", "valor", "sku", "ncm", "cfop", "categoria" ou "estoque").
+	Colunas map[string]string
+
+	// Simular apenas valida as linhas, sem gravar nenhum produto.
+	Simular bool
+}
+
+// decimal retorna o separador decimal para o separador de colunas informado.
+func (o OpcoesCSV) decimal(separador rune) (rune, error) {
+	switch {
+	case o.Decimal == 0 && separador == ';':
+		return ',', nil
+	case o.Decimal == 0:
+		return '.', nil
+	case o.Decimal != ',' && o.Decimal != '.':
+		return 0, fmt.Errorf("%w: separador decimal %q (use ',' ou '.')", ErrInvalido, o.Decimal)
+	}
+	return o.Decimal, nil
+}
+
+// ErroLinha é o erro de uma linha do arquivo importado.
+type ErroLinha struct {
+	Linha int
+	Err   error
+}
+
+// Error implementa a interface error.
+func (e *ErroLinha) Error() string {
+	return fmt.Sprintf("linha %d: %v", e.Linha, e.Err)
+}
+
+// Unwrap permite comparar o erro da linha com errors.Is e errors.As.
+func (e *ErroLinha) Unwrap() error {
+	return e.Err
+}
+
+// ResultadoImportacao resume a importação de um arquivo de produtos.
+type ResultadoImportacao struct {
+	Simulacao   bool // A importação apenas validou as linhas.
+	Linhas      int  // Linhas de dados lidas, sem o cabeçalho.
+	Adicionados int
+	Atualizados int
+	Inalterados int
+	Erros       []*ErroLinha // Linhas recusadas, que não foram gravadas.
+}
+
+// String retorna o resumo da importação, com os erros de cada linha recusada.
+func (r *ResultadoImportacao) String() string {
+	var sb strings.Builder
+	if r.Simulacao {
+		sb.WriteString("Simulação: nenhum produto foi gravado.\n")
+	}
+	sb.WriteString(fmt.Sprintf("%d linha(s): %d adicionado(s), %d atualizado(s), %d inalterado(s), %d com erro.\n",
+		r.Linhas, r.Adicionados, r.Atualizados, r.Inalterados, len(r.Erros)))
+	for _, e := range r.Erros {
+		sb.WriteString(e.Error() + "\n")
+	}
+	return sb.String()
+}
+
+// ImportarProdutosCSV lê produtos de um arquivo CSV e os adiciona ou atualiza no DAO de
+// produtos: cada linha atualiza o produto com o mesmo SKU ou, se não houver, com o mesmo
+// nome, e adiciona um novo produto caso nenhum seja encontrado. Células vazias mantêm o
+// valor atual do produto. Com a coluna de estoque, o estoque de um produto novo é registrado
+// como entrada e o de um produto existente é ajustado para o valor informado.
+//
+// Os cabeçalhos são reconhecidos sem diferenciar maiúsculas e acentos ("Preço", "Código",
+// "Quantidade"...) ou pelo mapeamento de OpcoesCSV.Colunas; colunas desconhecidas são
+// ignoradas. Os valores usam o separador decimal de OpcoesCSV.Decimal (ver
+// entidades.ParseDinheiroDecimal).
+//
+// As linhas com erro são recusadas e relatadas no resultado, sem impedir a gravação das
+// demais. O erro retornado indica apenas um arquivo ilegível ou um cabeçalho inválido.
+func ImportarProdutosCSV(r io.Reader, opcoes OpcoesCSV) (*ResultadoImportacao, error) {
+	conteudo, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	conteudo = bytes.TrimPrefix(conteudo, []byte("\ufeff")) // BOM gravado por planilhas.
+
+	leitor := csv.NewReader(bytes.NewReader(conteudo))
+	leitor.Comma = opcoes.Separador
+	if leitor.Comma == 0 {
+		leitor.Comma = detectarSeparador(conteudo)
+	}
+	leitor.FieldsPerRecord = -1
+	leitor.TrimLeadingSpace = true
+	decimal, err := opcoes.decimal(leitor.Comma)
+	if err != nil {
+		return nil, err
+	}
+
+	cabecalho, err := leitor.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: arquivo vazio", ErrInvalido)
+	}
+	if err != nil {
+		return nil, err
+	}
+	colunas, err := mapearColunas(cabecalho, opcoes.Colunas)
+	if err != nil {
+		return nil, err
+	}
+
+	importacao := &importacaoProdutos{
+		opcoes:    opcoes,
+		decimal:   decimal,
+		resultado: &ResultadoImportacao{Simulacao: opcoes.Simular},
+		vistos:    map[string]int{},
+	}
+	for {
+		registro, err := leitor.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		importacao.resultado.Linhas++
+		if err != nil {
+			var erroCSV *csv.ParseError
+			if errors.As(err, &erroCSV) {
+				importacao.falhar(erroCSV.Line, erroCSV.Err)
+				continue
+			}
+			return nil, err
+		}
+		linha, _ := leitor.FieldPos(0)
+		if err := importacao.importar(linha, celulas(registro, colunas)); err != nil {
+			importacao.falhar(linha, err)
+		}
+	}
+	return importacao.resultado, nil
+}
+
+// ExportarProdutosCSV grava todos os produtos, em ordem alfabética, em formato CSV com as
+// colunas nome, valor, sku, ncm, cfop, categoria e estoque. Os valores usam o separador
+// decimal de OpcoesCSV.Decimal: por padrão, vírgula com o separador ';', usado pelas
+// planilhas em português, e ponto nos demais casos.
+func ExportarProdutosCSV(w io.Writer, opcoes OpcoesCSV) error {
+	escritor := csv.NewWriter(w)
+	if opcoes.Separador != 0 {
+		escritor.Comma = opcoes.Separador
+	}
+	decimal, err := opcoes.decimal(escritor.Comma)
+	if err != nil {
+		return err
+	}
+	if err := escritor.Write(camposCSV); err != nil {
+		return err
+	}
+	produtos := GetInstance().Consultar().OrdenarPor(PorChave(func(p *entidades.Produto) string {
+		return NormalizarTexto(p.GetNome())
+	})).Listar()
+	for _, p := range produtos {
+		valor := p.GetValor().Decimal()
+		if decimal == ',' {
+			valor = strings.Replace(valor, ".", ",", 1)
+		}
+		registro := []string{p.GetNome(), valor, p.GetSKU(), p.GetNCM(), p.GetCFOP(), p.GetCategoria(), strconv.Itoa(p.GetEstoque())}
+		if err := escritor.Write(registro); err != nil {
+			return err
+		}
+	}
+	escritor.Flush()
+	return escritor.Error()
+}
+
+// detectarSeparador escolhe entre ';' e ',' o separador mais frequente na primeira linha.
+func detectarSeparador(conteudo []byte) rune {
+	primeira, _, _ := bytes.Cut(conteudo, []byte("\n"))
+	if bytes.Count(primeira, []byte(";")) > bytes.Count(primeira, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// mapearColunas relaciona cada campo do produto ao índice da sua coluna no cabeçalho.
+// Exige uma coluna de nome ou de SKU, que identifica o produto.
+func mapearColunas(cabecalho []string, mapeamento map[string]string) (map[string]int, error) {
+	personalizados := map[string]string{}
+	for titulo, campo := range mapeamento {
+		personalizados[NormalizarTexto(titulo)] = strings.ToLower(strings.TrimSpace(campo))
+	}
+
+	colunas := map[string]int{}
+	for i, titulo := range cabecalho {
+		normalizado := NormalizarTexto(titulo)
+		campo, ok := personalizados[normalizado]
+		if !ok {
+			campo, ok = cabecalhosCSV[normalizado]
+		}
+		if !ok {
+			continue // Coluna desconhecida.
+		}
+		if _, valido := cabecalhosCSV[campo]; !valido {
+			return nil, fmt.Errorf("%w: campo desconhecido %q para a coluna %q", ErrInvalido, campo, titulo)
+		}
+		if anterior, repetido := colunas[campo]; repetido {
+			return nil, fmt.Errorf("%w: as colunas %q e %q correspondem ao mesmo campo (%s)", ErrInvalido, cabecalho[anterior], titulo, campo)
+		}
+		colunas[campo] = i
+	}
+	if _, ok := colunas[campoNome]; !ok {
+		if _, ok := colunas[campoSKU]; !ok {
+			return nil, fmt.Errorf("%w: o cabeçalho precisa de uma coluna de nome ou de SKU", ErrInvalido)
+		}
+	}
+	return colunas, nil
+}
+
+// celulas retorna as células preenchidas do registro, por campo.
+func celulas(registro []string, colunas map[string]int) map[string]string {
+	valores := map[string]string{}
+	for campo, i := range colunas {
+		if i < len(registro) {
+			if valor := strings.TrimSpace(registro[i]); valor != "" {
+				valores[campo] = valor
+			}
+		}
+	}
+	return valores
+}
+
+// importacaoProdutos guarda o andamento de uma importação.
+type importacaoProdutos struct {
+	opcoes    OpcoesCSV
+	decimal   rune // Separador decimal dos valores.
+	resultado *ResultadoImportacao
+	vistos    map[string]int // Nome e SKU normalizados → linha em que apareceram.
+}
+
+// falhar registra o erro de uma linha.
+func (i *importacaoProdutos) falhar(linha int, err error) {
+	i.resultado.Erros = append(i.resultado.Erros, &ErroLinha{Linha: linha, Err: err})
+}
+
+// importar valida uma linha e, fora da simulação, grava o produto.
+func (i *importacaoProdutos) importar(linha int, valores map[string]string) error {
+	nome, sku := valores[campoNome], valores[campoSKU]
+	if nome == "" && sku == "" {
+		return errors.New("informe o nome ou o SKU do produto")
+	}
+	if err := i.registrarChaves(linha, nome, sku); err != nil {
+		return err
+	}
+
+	dao := GetInstance()
+	existente, err := i.buscar(nome, sku)
+	if err != nil {
+		return err
+	}
+	var produto *entidades.Produto
+	if existente != nil {
+		produto = existente.Clonar()
+	} else {
+		if nome == "" {
+			return fmt.Errorf("produto com SKU %q não encontrado; informe o nome para cadastrá-lo", sku)
+		}
+		if _, ok := valores[campoValor]; !ok {
+			return fmt.Errorf("informe o valor do novo produto %q", nome)
+		}
+		produto = entidades.NewProduto(nome, entidades.Dinheiro{})
+	}
+
+	if err := aplicarCelulas(produto, valores, i.decimal); err != nil {
+		return err
+	}
+	estoque := -1 // Sem coluna de estoque preenchida.
+	if texto, ok := valores[campoEstoque]; ok {
+		if estoque, err = strconv.Atoi(texto); err != nil || estoque < 0 {
+			return fmt.Errorf("estoque inválido: %q", texto)
+		}
+	}
+	if err := produto.Validar(); err != nil {
+		return err
+	}
+	if err := verificarUnicidadeProduto(produto); err != nil {
+		return err
+	}
+
+	alterado := existente == nil || *produto != *existente
+	ajustarEstoque := estoque >= 0 && (existente == nil && estoque > 0 || existente != nil && estoque != existente.GetEstoque())
+	if !alterado && !ajustarEstoque {
+		i.resultado.Inalterados++
+		return nil
+	}
+	if existente == nil {
+		i.resultado.Adicionados++
+	} else {
+		i.resultado.Atualizados++
+	}
+	if i.opcoes.Simular {
+		return nil
+	}
+
+	switch {
+	case existente == nil:
+		err = dao.AdicionarComEstoque(produto, max(estoque, 0), "estoque inicial (importação)")
+	case ajustarEstoque:
+		err = dao.AtualizarComEstoque(produto, estoque, "importação de produtos")
+	default:
+		err = dao.Atualizar(produto)
+	}
+	if err != nil {
+		if existente == nil {
+			i.resultado.Adicionados--
+		} else {
+			i.resultado.Atualizados--
+		}
+		return err
+	}
+	return nil
+}
+
+// registrarChaves recusa uma linha que repita o nome ou o SKU de uma linha anterior.
+func (i *importacaoProdutos) registrarChaves(linha int, nome, sku string) error {
+	chaves := []string{}
+	if nome != "" {
+		chaves = append(chaves, "nome:"+NormalizarTexto(nome))
+	}
+	if sku != "" {
+		chaves = append(chaves, "sku:"+normalizarSKU(sku))
+	}
+	for _, chave := range chaves {
+		if anterior, ok := i.vistos[chave]; ok {
+			return fmt.Errorf("produto repetido no arquivo (linha %d)", anterior)
+		}
+	}
+	for _, chave := range chaves {
+		i.vistos[chave] = linha
+	}
+	return nil
+}
+
+// buscar retorna o produto com o SKU ou, se não houver, com o nome informado,
+// ou nil se nenhum for encontrado.
+func (i *importacaoProdutos) buscar(nome, sku string) (*entidades.Produto, error) {
+	dao := GetInstance()
+	if sku != "" {
+		produto, err := dao.BuscarPorSKU(sku)
+		if err == nil || !errors.Is(err, ErrNaoEncontrado) {
+			return produto, err
+		}
+	}
+	if nome != "" {
+		produto, err := dao.BuscarPorNome(nome)
+		if err == nil || !errors.Is(err, ErrNaoEncontrado) {
+			return produto, err
+		}
+	}
+	return nil, nil
+}
+
+// aplicarCelulas copia para o produto os campos preenchidos na linha, exceto o estoque,
+// lendo o valor com o separador decimal informado.
+func aplicarCelulas(produto *entidades.Produto, valores map[string]string, decimal rune) error {
+	if nome, ok := valores[campoNome]; ok {
+		produto.SetNome(nome)
+	}
+	if texto, ok := valores[campoValor]; ok {
+		valor, err := entidades.ParseDinheiroDecimal(texto, entidades.MoedaPadrao, decimal)
+		if err != nil {
+			return fmt.Errorf("%w (separador decimal %q)", err, decimal)
+		}
+		produto.SetValor(valor)
+	}
+	if sku, ok := valores[campoSKU]; ok {
+		produto.SetSKU(sku)
+	}
+	if ncm, ok := valores[campoNCM]; ok {
+		produto.SetNCM(ncm)
+	}
+	if cfop, ok := valores[campoCFOP]; ok {
+		produto.SetCFOP(cfop)
+	}
+	if categoria, ok := valores[campoCategoria]; ok {
+		produto.SetCategoria(categoria)
+	}
+	return nil
+}
+
+// verificarUnicidadeProduto antecipa, também na simulação, o *ErroUnicidade que o DAO
+// retornaria para um produto que repete o nome ou o SKU de outro.
+func verificarUnicidadeProduto(produto *entidades.Produto) error {
+	dao := GetInstance()
+	if outro, err := dao.BuscarPorNome(produto.GetNome()); err == nil && outro.GetID() != produto.GetID() {
+		return &ErroUnicidade{Restricao: indiceNome, Chave: NormalizarTexto(produto.GetNome())}
+	}
+	if produto.GetSKU() != "" {
+		if outro, err := dao.BuscarPorSKU(produto.GetSKU()); err == nil && outro.GetID() != produto.GetID() {
+			return &ErroUnicidade{Restricao: indiceSKU, Chave: normalizarSKU(produto.GetSKU())}
+		}
+	}
+	return nil
+}
@@ -3,54 +3,306 @@ package data
 import (
 	"clp-go-version/entidades"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // DAO é uma estrutura genérica para manipular entidades.
+// Quando possui um Storage, cada alteração é gravada nele logo após ser aplicada.
+// Os IDs das entidades são atribuídos pelo IDGenerator do DAO no momento da inserção.
+//
+// O DAO pode ser usado por várias goroutines ao mesmo tempo: leituras compartilham um
+// sync.RWMutex e alterações são exclusivas. As leituras retornam cópias da lista de
+// entidades, nunca a lista interna.
+//
+// As buscas por ID usam um índice hash, e outros índices podem ser criados com AddIndex.
+// Os índices são atualizados a cada inserção, atualização e remoção; alterar diretamente
+// um campo usado como chave de uma entidade já armazenada, sem chamar Atualizar, não
+// atualiza os índices.
+//
+// Cada entidade possui uma versão, incrementada a cada atualização. Atualizar só aceita
+// entidades com a mesma versão armazenada (controle de concorrência otimista).
+//
+// Restrições de unicidade, criadas com AddUnique, são verificadas em Adicionar e Atualizar.
 type DAO[E entidades.Entidade] struct {
-	Dados []E
+	mu      sync.RWMutex
+	dados   []E
+	porID   map[int64]E
+	indices map[string]*indice[E]
+	storage Storage[E]
+	gerador IDGenerator
 }
 
-// NewDAO cria uma nova instância de DAO.
+// NewDAO cria uma nova instância de DAO mantida apenas em memória,
+// com IDs sequenciais.
 func NewDAO[E entidades.Entidade]() *DAO[E] {
-	return &DAO[E]{}
+	return &DAO[E]{
+		porID:   map[int64]E{},
+		indices: map[string]*indice[E]{},
+		gerador: NewGeradorSequencial(),
+	}
+}
+
+// NewDAOPersistente cria um DAO com os dados carregados de storage,
+// com IDs sequenciais a partir do maior ID carregado.
+func NewDAOPersistente[E entidades.Entidade](storage Storage[E]) (*DAO[E], error) {
+	dados, err := storage.Carregar()
+	if err != nil {
+		return nil, err
+	}
+	d := NewDAO[E]()
+	d.dados, d.storage = dados, storage
+	for _, e := range dados {
+		d.porID[e.GetID()] = e
+	}
+	d.SetGerador(NewGeradorSequencial())
+	return d, nil
+}
+
+// AddIndex cria (ou recria) um índice secundário com o nome informado.
+// A função chave calcula, para cada entidade, o valor pelo qual ela será encontrada
+// em BuscarPorIndice. Várias entidades podem compartilhar a mesma chave.
+func (d *DAO[E]) AddIndex(nome string, chave func(E) string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.indices[nome] = newIndice(chave, false, d.dados)
+}
+
+// AddUnique cria um índice, como AddIndex, que também é uma restrição de unicidade:
+// Adicionar e Atualizar retornam um *ErroUnicidade se outra entidade já tiver a mesma
+// chave. Chaves vazias não são verificadas, o que permite campos opcionais.
+// Entidades repetidas já existentes no DAO são mantidas, mas só podem ser atualizadas
+// depois que a repetição for desfeita.
+func (d *DAO[E]) AddUnique(nome string, chave func(E) string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.indices[nome] = newIndice(chave, true, d.dados)
 }
 
-// GetDados retorna a lista de entidades armazenadas.
+// BuscarPorIndice retorna as entidades cuja chave no índice informado é igual a chave.
+// Retorna nil se o índice não existir ou se nenhuma entidade tiver essa chave.
+func (d *DAO[E]) BuscarPorIndice(nome, chave string) []E {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if idx, ok := d.indices[nome]; ok {
+		return idx.buscar(chave)
+	}
+	return nil
+}
+
+// SetGerador define o gerador de IDs usado nas próximas inserções.
+// Os IDs já existentes no DAO são informados ao gerador para que não sejam reutilizados.
+func (d *DAO[E]) SetGerador(gerador IDGenerator) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, e := range d.dados {
+		gerador.Observar(e.GetID())
+	}
+	d.gerador = gerador
+}
+
+// GetDados retorna uma cópia da lista de entidades armazenadas.
+// A cópia é um retrato do DAO no momento da chamada e não é afetada por alterações
+// posteriores; as entidades em si, por serem ponteiros, continuam compartilhadas.
 func (d *DAO[E]) GetDados() []E {
-	return d.Dados
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return slices.Clone(d.dados)
 }
 
-// Adicionar adiciona uma entidade ao DAO.
-func (d *DAO[E]) Adicionar(entidade E) {
-	d.Dados = append(d.Dados, entidade)
+// Adicionar atribui um novo ID e a versão 1 à entidade e a adiciona ao DAO.
+// Retorna ErrInvalido se a entidade não passar na validação e ErrDuplicado se ela
+// já tiver sido adicionada. Se a gravação falhar, a entidade não permanece no DAO e
+// volta a ter o ID e a versão anteriores.
+func (d *DAO[E]) Adicionar(entidade E) error {
+	if err := validar(entidade); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, existe := d.porID[entidade.GetID()]; existe {
+		return fmt.Errorf("%w: id %d", ErrDuplicado, entidade.GetID())
+	}
+	if err := d.verificarUnicidade(entidade); err != nil {
+		return err
+	}
+	id, versao := entidade.GetID(), entidade.GetVersao()
+	entidade.SetID(d.novoID())
+	entidade.SetVersao(1)
+	d.dados = append(d.dados, entidade)
+	err := d.gravar(Operacao[E]{Tipo: OpAdicionar, ID: entidade.GetID(), Entidade: entidade})
+	if err != nil {
+		entidade.SetID(id)
+		entidade.SetVersao(versao)
+		d.dados = d.dados[:len(d.dados)-1]
+		return err
+	}
+
+	d.porID[entidade.GetID()] = entidade
+	for _, idx := range d.indices {
+		idx.adicionar(entidade)
+	}
+	return nil
 }
 
 // Buscar procura uma entidade pelo ID.
-func (d *DAO[E]) Buscar(id int64) *E {
-	for i := range d.Dados {
-		if d.Dados[i].GetID() == id {
-			return &d.Dados[i]
-		}
+// Retorna ErrNaoEncontrado se não houver entidade com esse ID.
+func (d *DAO[E]) Buscar(id int64) (E, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if e, ok := d.porID[id]; ok {
+		return e, nil
+	}
+	var vazio E
+	return vazio, fmt.Errorf("%w: id %d", ErrNaoEncontrado, id)
+}
+
+// Atualizar substitui a entidade de mesmo ID pela entidade informada.
+// A entidade deve ter sido lida na versão atualmente armazenada; caso contrário,
+// retorna um *ErroConflito e nada é alterado. Em caso de sucesso, a versão é incrementada.
+// Para evitar alterar a entidade armazenada antes da confirmação, edite uma cópia dela.
+// Retorna ErrNaoEncontrado se o ID não existir e ErrInvalido se a entidade não passar na validação.
+func (d *DAO[E]) Atualizar(entidade E) error {
+	if err := validar(entidade); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id := entidade.GetID()
+	atual, ok := d.porID[id]
+	if !ok {
+		return fmt.Errorf("%w: id %d", ErrNaoEncontrado, id)
+	}
+	versao := entidade.GetVersao()
+	if versao != atual.GetVersao() {
+		return &ErroConflito{ID: id, VersaoEsperada: versao, VersaoAtual: atual.GetVersao()}
+	}
+	if err := d.verificarUnicidade(entidade); err != nil {
+		return err
+	}
+
+	anteriores := slices.Clone(d.dados)
+	entidade.SetVersao(versao + 1)
+	d.dados = aplicarOperacao(d.dados, Operacao[E]{Tipo: OpAtualizar, ID: id, Entidade: entidade})
+	if err := d.gravar(Operacao[E]{Tipo: OpAtualizar, ID: id, Entidade: entidade}); err != nil {
+		entidade.SetVersao(versao)
+		d.dados = anteriores
+		return err
+	}
+
+	d.porID[id] = entidade
+	for _, idx := range d.indices {
+		idx.remover(id)
+		idx.adicionar(entidade)
 	}
 	return nil
 }
 
 // Remover remove uma entidade pelo ID.
-func (d *DAO[E]) Remover(id int64) {
+// Retorna ErrNaoEncontrado se não houver entidade com esse ID.
+// Se a gravação falhar, os dados anteriores são mantidos.
+func (d *DAO[E]) Remover(id int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.porID[id]; !ok {
+		return fmt.Errorf("%w: id %d", ErrNaoEncontrado, id)
+	}
+	return d.remover(id)
+}
+
+// RemoverSe remove, em uma única operação exclusiva, todas as entidades para as quais
+// condicao retorna true, e retorna quantas foram removidas.
+// Se uma gravação falhar, as remoções seguintes não são feitas.
+func (d *DAO[E]) RemoverSe(condicao func(E) bool) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	removidas := 0
+	for _, e := range slices.Clone(d.dados) {
+		if condicao(e) {
+			if err := d.remover(e.GetID()); err != nil {
+				return removidas, err
+			}
+			removidas++
+		}
+	}
+	return removidas, nil
+}
+
+// remover retira a entidade com o ID informado. Deve ser chamado com o lock de escrita.
+func (d *DAO[E]) remover(id int64) error {
+	anteriores := d.dados
 	filtrados := []E{}
-	for _, e := range d.Dados {
+	for _, e := range d.dados {
 		if e.GetID() != id {
 			filtrados = append(filtrados, e)
 		}
 	}
-	d.Dados = filtrados
+	d.dados = filtrados
+	err := d.gravar(Operacao[E]{Tipo: OpRemover, ID: id})
+	if err != nil {
+		d.dados = anteriores
+		return err
+	}
+
+	delete(d.porID, id)
+	for _, idx := range d.indices {
+		idx.remover(id)
+	}
+	return nil
+}
+
+// verificarUnicidade confere as restrições de unicidade para a entidade.
+// Deve ser chamado com o lock já obtido.
+func (d *DAO[E]) verificarUnicidade(entidade E) error {
+	for nome, idx := range d.indices {
+		if chave, repetida := idx.conflito(entidade); repetida {
+			return &ErroUnicidade{Restricao: nome, Chave: chave}
+		}
+	}
+	return nil
+}
+
+// validar aplica a validação da entidade, se ela implementar entidades.Validavel.
+func validar[E entidades.Entidade](entidade E) error {
+	if v, ok := any(entidade).(entidades.Validavel); ok {
+		if err := v.Validar(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalido, err)
+		}
+	}
+	return nil
+}
+
+// novoID obtém do gerador um ID que ainda não esteja em uso neste DAO.
+// Deve ser chamado com o lock de escrita.
+func (d *DAO[E]) novoID() int64 {
+	for {
+		id := d.gerador.ProximoID()
+		if _, existe := d.porID[id]; !existe {
+			return id
+		}
+	}
+}
+
+// gravar repassa a operação ao Storage, se houver. Deve ser chamado com o lock de escrita,
+// o que também serializa as chamadas ao Storage.
+func (d *DAO[E]) gravar(op Operacao[E]) error {
+	if d.storage == nil {
+		return nil
+	}
+	return d.storage.Gravar(op, d.dados)
 }
 
 // String retorna uma representação textual do DAO.
 func (d *DAO[E]) String() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	var sb strings.Builder
-	for _, e := range d.Dados {
+	for _, e := range d.dados {
 		sb.WriteString(fmt.Sprintf("\n%s", e.String()))
 	}
 	return sb.String()
@@ -0,0 +1,147 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// DAOCaixa é um singleton que controla as sessões de caixa (ver entidades.Caixa).
+// Há no máximo um caixa aberto por vez; enquanto nenhum estiver aberto, as vendas são
+// recusadas com ErrCaixaFechado. As aberturas, movimentações e fechamentos são
+// serializados por um mutex próprio.
+type DAOCaixa struct {
+	mu  sync.Mutex             // Serializa a abertura, as movimentações e o fechamento.
+	dao *DAO[*entidades.Caixa] // Sessões de caixa, abertas e fechadas.
+}
+
+var caixaInstance *DAOCaixa // Instância única do DAOCaixa.
+var caixaOnce sync.Once     // Garante a inicialização única do singleton.
+
+// GetCaixaInstance retorna a instância singleton de DAOCaixa.
+// Os caixas são gravados em log de escrita antecipada (caixas.log e caixas.snapshot.json
+// no diretório de dados).
+func GetCaixaInstance() *DAOCaixa {
+	caixaOnce.Do(func() {
+		storage := NewStorageLog[*entidades.Caixa](CaminhoDados("caixas"), CompactacaoPadrao)
+		dao, err := NewDAOPersistente(storage)
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar os caixas: %v", err))
+		}
+		caixaInstance = &DAOCaixa{dao: dao}
+	})
+	return caixaInstance
+}
+
+// Abrir abre um caixa com o operador e o fundo de troco informados.
+// Retorna ErrCaixaAberto se já houver um caixa aberto.
+func (d *DAOCaixa) Abrir(operador string, fundoTroco entidades.Dinheiro) (*entidades.Caixa, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if atual, err := d.atual(); err == nil {
+		return nil, fmt.Errorf("%w: caixa %d de %s", ErrCaixaAberto, atual.GetID(), atual.Operador)
+	}
+	caixa := entidades.NewCaixa(operador, fundoTroco)
+	if err := d.dao.Adicionar(caixa); err != nil {
+		return nil, err
+	}
+	return caixa, nil
+}
+
+// Atual retorna o caixa aberto, ou ErrCaixaFechado se não houver nenhum.
+func (d *DAOCaixa) Atual() (*entidades.Caixa, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.atual()
+}
+
+// Sangria retira dinheiro do caixa aberto. O valor não pode exceder o dinheiro esperado
+// na gaveta.
+func (d *DAOCaixa) Sangria(valor entidades.Dinheiro, motivo string) (*entidades.Caixa, error) {
+	return d.movimentar(entidades.MovimentoSangria, valor, motivo)
+}
+
+// Suprimento reforça o dinheiro do caixa aberto.
+func (d *DAOCaixa) Suprimento(valor entidades.Dinheiro, motivo string) (*entidades.Caixa, error) {
+	return d.movimentar(entidades.MovimentoSuprimento, valor, motivo)
+}
+
+// Fechar encerra o caixa aberto com os valores contados em cada forma de pagamento,
+// registrando também os valores esperados (ver entidades.Caixa.CalcularEsperado).
+// Retorna o caixa fechado, cuja conferência compara os dois.
+func (d *DAOCaixa) Fechar(contado map[entidades.FormaPagamento]entidades.Dinheiro) (*entidades.Caixa, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	atual, err := d.atual()
+	if err != nil {
+		return nil, err
+	}
+	caixa := atual.Clonar()
+	if err := caixa.Fechar(d.esperado(caixa), contado); err != nil {
+		return nil, err
+	}
+	if err := d.dao.Atualizar(caixa); err != nil {
+		return nil, err
+	}
+	return caixa, nil
+}
+
+// Esperado calcula os valores esperados no caixa informado, em cada forma de pagamento,
+// a partir das vendas atribuídas a ele.
+func (d *DAOCaixa) Esperado(caixa *entidades.Caixa) map[entidades.FormaPagamento]entidades.Dinheiro {
+	return d.esperado(caixa)
+}
+
+// Buscar retorna o caixa com o ID informado, ou ErrNaoEncontrado.
+func (d *DAOCaixa) Buscar(id int64) (*entidades.Caixa, error) {
+	return d.dao.Buscar(id)
+}
+
+// Consultar inicia uma consulta sobre os caixas.
+func (d *DAOCaixa) Consultar() *Consulta[*entidades.Caixa] {
+	return d.dao.Consultar()
+}
+
+// String retorna uma representação textual dos caixas.
+func (d *DAOCaixa) String() string {
+	return d.dao.String()
+}
+
+// movimentar registra uma sangria ou um suprimento no caixa aberto.
+func (d *DAOCaixa) movimentar(tipo entidades.TipoMovimentoCaixa, valor entidades.Dinheiro, motivo string) (*entidades.Caixa, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	atual, err := d.atual()
+	if err != nil {
+		return nil, err
+	}
+	if tipo == entidades.MovimentoSangria {
+		if disponivel := d.esperado(atual)[entidades.PagamentoDinheiro]; valor.Comparar(disponivel) > 0 {
+			return nil, fmt.Errorf("%w: a sangria de %s excede o dinheiro em caixa (%s)", ErrInvalido, valor, disponivel)
+		}
+	}
+	caixa := atual.Clonar()
+	if err := caixa.Movimentar(tipo, valor, motivo); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalido, err)
+	}
+	if err := d.dao.Atualizar(caixa); err != nil {
+		return nil, err
+	}
+	return caixa, nil
+}
+
+// atual retorna o caixa aberto. Deve ser chamado com d.mu obtido.
+func (d *DAOCaixa) atual() (*entidades.Caixa, error) {
+	abertos := d.dao.Consultar().Onde((*entidades.Caixa).Aberto).Limite(1).Listar()
+	if len(abertos) == 0 {
+		return nil, ErrCaixaFechado
+	}
+	return abertos[0], nil
+}
+
+// esperado calcula os valores esperados no caixa a partir das suas vendas.
+func (d *DAOCaixa) esperado(caixa *entidades.Caixa) map[entidades.FormaPagamento]entidades.Dinheiro {
+	vendas := GetVendaInstance().dao.BuscarPorIndice(indiceCaixa, strconv.FormatInt(caixa.GetID(), 10))
+	return caixa.CalcularEsperado(vendas)
+}
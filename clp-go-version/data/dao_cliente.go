@@ -0,0 +1,91 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DAOCliente é um singleton que gerencia os clientes.
+// Os clientes são indexados pelo documento (CPF ou CNPJ, apenas dígitos), que é único:
+// Adicionar e Atualizar retornam um *ErroUnicidade para um documento já cadastrado.
+type DAOCliente struct {
+	dao *DAO[*entidades.Cliente]
+}
+
+// indiceDocumento é o nome do índice único de documentos de clientes.
+const indiceDocumento = "documento"
+
+var clienteInstance *DAOCliente // Instância única do DAOCliente.
+var clienteOnce sync.Once       // Garante a inicialização única do singleton.
+
+// GetClienteInstance retorna a instância singleton de DAOCliente.
+// Os clientes são gravados em clientes.json no diretório de dados.
+func GetClienteInstance() *DAOCliente {
+	clienteOnce.Do(func() {
+		storage := NewStorageJSON[*entidades.Cliente](CaminhoDados("clientes.json"))
+		dao, err := NewDAOPersistente(storage)
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar os clientes: %v", err))
+		}
+		dao.AddUnique(indiceDocumento, func(c *entidades.Cliente) string {
+			return entidades.NormalizarDocumento(c.GetDocumento())
+		})
+		clienteInstance = &DAOCliente{dao: dao}
+	})
+	return clienteInstance
+}
+
+// Adicionar adiciona um Cliente ao DAO.
+// Retorna ErrInvalido se o CPF ou CNPJ for inválido.
+func (d *DAOCliente) Adicionar(cliente *entidades.Cliente) error {
+	return d.dao.Adicionar(cliente)
+}
+
+// Buscar por ID retorna o Cliente com o ID especificado.
+// Retorna ErrNaoEncontrado se o cliente não existir.
+func (d *DAOCliente) Buscar(id int64) (*entidades.Cliente, error) {
+	return d.dao.Buscar(id)
+}
+
+// BuscarPorDocumento retorna o Cliente com o CPF ou CNPJ informado, com ou sem pontuação.
+// Retorna ErrNaoEncontrado caso não encontre.
+func (d *DAOCliente) BuscarPorDocumento(documento string) (*entidades.Cliente, error) {
+	if encontrados := d.dao.BuscarPorIndice(indiceDocumento, entidades.NormalizarDocumento(documento)); len(encontrados) > 0 {
+		return encontrados[0], nil
+	}
+	return nil, fmt.Errorf("%w: cliente com documento %q", ErrNaoEncontrado, documento)
+}
+
+// BuscarPorNome retorna os clientes cujo nome contém o texto informado, sem diferenciar
+// maiúsculas, minúsculas e acentos, em ordem alfabética.
+func (d *DAOCliente) BuscarPorNome(nome string) []*entidades.Cliente {
+	trecho := NormalizarTexto(nome)
+	return d.dao.Consultar().
+		Onde(func(c *entidades.Cliente) bool { return strings.Contains(NormalizarTexto(c.GetNome()), trecho) }).
+		OrdenarPor(PorChave(func(c *entidades.Cliente) string { return NormalizarTexto(c.GetNome()) })).
+		Listar()
+}
+
+// Atualizar grava as alterações de um Cliente existente.
+// Retorna um *ErroConflito se o cliente foi alterado desde que foi lido.
+func (d *DAOCliente) Atualizar(cliente *entidades.Cliente) error {
+	return d.dao.Atualizar(cliente)
+}
+
+// Remover por ID remove o Cliente com o ID especificado.
+// As vendas do cliente continuam registradas com o seu nome.
+func (d *DAOCliente) Remover(id int64) error {
+	return d.dao.Remover(id)
+}
+
+// Consultar inicia uma consulta sobre os clientes.
+func (d *DAOCliente) Consultar() *Consulta[*entidades.Cliente] {
+	return d.dao.Consultar()
+}
+
+// String retorna uma representação textual dos clientes.
+func (d *DAOCliente) String() string {
+	return d.dao.String()
+}
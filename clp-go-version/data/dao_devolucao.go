@@ -0,0 +1,90 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// DAODevolucao é um singleton que registra as devoluções de itens de vendas.
+// Cada devolução devolve os produtos ao estoque e serve de nota de crédito ao cliente;
+// a venda original permanece inalterada.
+type DAODevolucao struct {
+	dao *DAO[*entidades.Devolucao]
+}
+
+var devolucaoInstance *DAODevolucao // Instância única do DAODevolucao.
+var devolucaoOnce sync.Once         // Garante a inicialização única do singleton.
+
+// GetDevolucaoInstance retorna a instância singleton de DAODevolucao.
+// As devoluções são gravadas em log de escrita antecipada (devolucoes.log e
+// devolucoes.snapshot.json no diretório de dados) e indexadas pela venda.
+func GetDevolucaoInstance() *DAODevolucao {
+	devolucaoOnce.Do(func() {
+		storage := NewStorageLog[*entidades.Devolucao](CaminhoDados("devolucoes"), CompactacaoPadrao)
+		dao, err := NewDAOPersistente(storage)
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar as devoluções: %v", err))
+		}
+		dao.AddIndex("venda", chaveVenda)
+		devolucaoInstance = &DAODevolucao{dao: dao}
+	})
+	return devolucaoInstance
+}
+
+// chaveVenda é a chave do índice de devoluções por venda.
+func chaveVenda(d *entidades.Devolucao) string {
+	return strconv.FormatInt(d.VendaID, 10)
+}
+
+// Adicionar registra a devolução e devolve os itens ao estoque.
+// Retorna ErrInvalido se a venda não estiver finalizada ou se alguma quantidade exceder
+// a vendida, descontadas as devoluções anteriores da mesma venda.
+func (d *DAODevolucao) Adicionar(devolucao *entidades.Devolucao) error {
+	estoque := GetEstoqueInstance()
+	estoque.mu.Lock()
+	defer estoque.mu.Unlock()
+
+	venda, err := GetVendaInstance().Buscar(devolucao.VendaID)
+	if err != nil {
+		return err
+	}
+	if err := entidades.VerificarDevolucao(venda, d.PorVenda(venda.GetID()), devolucao); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalido, err)
+	}
+	if err := d.dao.Adicionar(devolucao); err != nil {
+		return err
+	}
+
+	variacoes := map[int64]int{}
+	for _, item := range devolucao.GetItens() {
+		variacoes[item.ProdutoID] += item.Quantidade
+	}
+	motivo := fmt.Sprintf("devolução %d da venda %d", devolucao.GetID(), venda.GetID())
+	if err := estoque.aplicarVenda(venda.GetID(), variacoes, motivo); err != nil {
+		return desfeito(err, d.dao.Remover(devolucao.GetID()))
+	}
+	return nil
+}
+
+// Buscar por ID retorna a Devolucao com o ID especificado.
+// Retorna ErrNaoEncontrado se a devolução não existir.
+func (d *DAODevolucao) Buscar(id int64) (*entidades.Devolucao, error) {
+	return d.dao.Buscar(id)
+}
+
+// PorVenda retorna as devoluções da venda informada, da mais antiga à mais recente.
+func (d *DAODevolucao) PorVenda(vendaID int64) []*entidades.Devolucao {
+	return d.dao.BuscarPorIndice("venda", strconv.FormatInt(vendaID, 10))
+}
+
+// Consultar inicia uma consulta sobre as devoluções.
+func (d *DAODevolucao) Consultar() *Consulta[*entidades.Devolucao] {
+	return d.dao.Consultar()
+}
+
+// String retorna uma representação textual das devoluções.
+func (d *DAODevolucao) String() string {
+	return d.dao.String()
+}
@@ -0,0 +1,200 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DAOEstoque é um singleton que controla o estoque dos produtos.
+// O saldo de cada produto fica em Produto.Estoque, e toda alteração do saldo é registrada
+// como um MovimentoEstoque (entrada, saída ou ajuste), formando o histórico do estoque.
+// As alterações são serializadas por um mutex próprio, de modo que a verificação do saldo
+// e a baixa de todos os itens de uma venda acontecem sem interferência de outras operações.
+type DAOEstoque struct {
+	mu  sync.Mutex                        // Serializa as alterações de estoque.
+	dao *DAO[*entidades.MovimentoEstoque] // Histórico de movimentos.
+}
+
+var estoqueInstance *DAOEstoque // Instância única do DAOEstoque.
+var estoqueOnce sync.Once       // Garante a inicialização única do singleton.
+
+// GetEstoqueInstance retorna a instância singleton de DAOEstoque.
+// Os movimentos são gravados em log de escrita antecipada (movimentos_estoque.log e
+// movimentos_estoque.snapshot.json no diretório de dados).
+func GetEstoqueInstance() *DAOEstoque {
+	estoqueOnce.Do(func() {
+		storage := NewStorageLog[*entidades.MovimentoEstoque](CaminhoDados("movimentos_estoque"), CompactacaoPadrao)
+		dao, err := NewDAOPersistente(storage)
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar os movimentos de estoque: %v", err))
+		}
+		estoqueInstance = &DAOEstoque{dao: dao}
+	})
+	return estoqueInstance
+}
+
+// Entrada soma uma quantidade positiva ao estoque do produto.
+func (d *DAOEstoque) Entrada(produtoID int64, quantidade int, motivo string) (*entidades.MovimentoEstoque, error) {
+	if quantidade <= 0 {
+		return nil, fmt.Errorf("%w: a quantidade de entrada deve ser maior que zero", ErrInvalido)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.movimentar(produtoID, entidades.MovimentoEntrada, quantidade, motivo, 0)
+}
+
+// Saida retira uma quantidade positiva do estoque do produto.
+// Retorna um *ErroEstoqueInsuficiente se o saldo não for suficiente.
+func (d *DAOEstoque) Saida(produtoID int64, quantidade int, motivo string) (*entidades.MovimentoEstoque, error) {
+	if quantidade <= 0 {
+		return nil, fmt.Errorf("%w: a quantidade de saída deve ser maior que zero", ErrInvalido)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.movimentar(produtoID, entidades.MovimentoSaida, -quantidade, motivo, 0)
+}
+
+// Ajustar corrige o saldo do produto para a quantidade contada, registrando a diferença.
+func (d *DAOEstoque) Ajustar(produtoID int64, saldo int, motivo string) (*entidades.MovimentoEstoque, error) {
+	if saldo < 0 {
+		return nil, fmt.Errorf("%w: o saldo não pode ser negativo", ErrInvalido)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	produto, err := GetInstance().Buscar(produtoID)
+	if err != nil {
+		return nil, err
+	}
+	return d.movimentar(produtoID, entidades.MovimentoAjuste, saldo-produto.GetEstoque(), motivo, 0)
+}
+
+// Movimentos retorna o histórico de movimentos de um produto, do mais recente ao mais antigo.
+// Com produtoID igual a zero, retorna os movimentos de todos os produtos.
+func (d *DAOEstoque) Movimentos(produtoID int64) []*entidades.MovimentoEstoque {
+	consulta := d.dao.Consultar().OrdenarPor(Decrescente(PorChave(func(m *entidades.MovimentoEstoque) int64 {
+		return m.ID
+	})))
+	if produtoID != 0 {
+		consulta.Onde(func(m *entidades.MovimentoEstoque) bool { return m.ProdutoID == produtoID })
+	}
+	return consulta.Listar()
+}
+
+// Consultar inicia uma consulta sobre os movimentos de estoque.
+func (d *DAOEstoque) Consultar() *Consulta[*entidades.MovimentoEstoque] {
+	return d.dao.Consultar()
+}
+
+// String retorna uma representação textual do histórico de movimentos.
+func (d *DAOEstoque) String() string {
+	return d.dao.String()
+}
+
+// movimentar altera o saldo do produto em variacao unidades e registra o movimento.
+// Deve ser chamado com d.mu obtido.
+func (d *DAOEstoque) movimentar(produtoID int64, tipo entidades.TipoMovimento, variacao int, motivo string, vendaID int64) (*entidades.MovimentoEstoque, error) {
+	daoProduto := GetInstance()
+	atual, err := daoProduto.Buscar(produtoID)
+	if err != nil {
+		return nil, err
+	}
+	if atual.GetEstoque()+variacao < 0 {
+		return nil, &ErroEstoqueInsuficiente{
+			ProdutoID:  produtoID,
+			Produto:    atual.GetNome(),
+			Disponivel: atual.GetEstoque(),
+			Solicitado: -variacao,
+		}
+	}
+
+	produto := atual.Clonar()
+	produto.SetEstoque(atual.GetEstoque() + variacao)
+	if err := daoProduto.Atualizar(produto); err != nil {
+		return nil, err
+	}
+
+	movimento := entidades.NewMovimentoEstoque(produto, tipo, variacao, motivo)
+	movimento.VendaID = vendaID
+	if err := d.dao.Adicionar(movimento); err != nil {
+		// Sem o registro no histórico, o saldo volta ao valor anterior.
+		revertido := produto.Clonar()
+		revertido.SetEstoque(atual.GetEstoque())
+		return nil, desfeito(err, daoProduto.Atualizar(revertido))
+	}
+	return movimento, nil
+}
+
+// verificarVenda confere se há estoque para as variações informadas (produto → variação).
+// Variações positivas (devoluções ao estoque) de produtos já excluídos são ignoradas.
+// Deve ser chamado com d.mu obtido.
+func (d *DAOEstoque) verificarVenda(variacoes map[int64]int) error {
+	for produtoID, variacao := range variacoes {
+		if variacao >= 0 {
+			continue
+		}
+		produto, err := GetInstance().Buscar(produtoID)
+		if err != nil {
+			return err
+		}
+		if produto.GetEstoque()+variacao < 0 {
+			return &ErroEstoqueInsuficiente{
+				ProdutoID:  produtoID,
+				Produto:    produto.GetNome(),
+				Disponivel: produto.GetEstoque(),
+				Solicitado: -variacao,
+			}
+		}
+	}
+	return nil
+}
+
+// aplicarVenda registra os movimentos de uma venda: saídas para as variações negativas e
+// entradas para as positivas. Se um movimento falhar, os já aplicados são desfeitos, e as
+// falhas ao desfazê-los são acrescentadas ao erro (ver desfeito).
+// Deve ser chamado com d.mu obtido e após verificarVenda.
+func (d *DAOEstoque) aplicarVenda(vendaID int64, variacoes map[int64]int, motivo string) error {
+	aplicadas := map[int64]int{}
+	for produtoID, variacao := range variacoes {
+		if variacao == 0 {
+			continue
+		}
+		tipo := entidades.MovimentoSaida
+		if variacao > 0 {
+			tipo = entidades.MovimentoEntrada
+			if _, err := GetInstance().Buscar(produtoID); err != nil {
+				continue // Produto excluído: não há estoque a devolver.
+			}
+		}
+		if _, err := d.movimentar(produtoID, tipo, variacao, motivo, vendaID); err != nil {
+			var estornos []error
+			for id, v := range aplicadas {
+				if _, errEstorno := d.movimentar(id, entidades.MovimentoAjuste, -v, "estorno de "+motivo, vendaID); errEstorno != nil {
+					estornos = append(estornos, errEstorno)
+				}
+			}
+			return desfeito(err, errors.Join(estornos...))
+		}
+		aplicadas[produtoID] = variacao
+	}
+	return nil
+}
+
+// variacoesVenda calcula a variação de estoque de cada produto ao trocar a venda anterior
+// pela nova: os itens da anterior voltam ao estoque e os da nova saem dele.
+// Qualquer uma das duas pode ser nil.
+func variacoesVenda(anterior, nova *entidades.Venda) map[int64]int {
+	variacoes := map[int64]int{}
+	if anterior != nil {
+		for _, item := range anterior.GetItens() {
+			variacoes[item.Produto.GetID()] += item.Quantidade
+		}
+	}
+	if nova != nil {
+		for _, item := range nova.GetItens() {
+			variacoes[item.Produto.GetID()] -= item.Quantidade
+		}
+	}
+	return variacoes
+}
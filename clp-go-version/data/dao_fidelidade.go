@@ -0,0 +1,267 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DAOFidelidade é um singleton que controla os pontos de fidelidade dos clientes.
+// Toda alteração do saldo é registrada como um MovimentoPontos (acúmulo, resgate,
+// expiração ou estorno), formando o extrato de cada cliente.
+//
+// Os pontos são ganhos na finalização de uma venda com cliente identificado, na proporção
+// de ProgramaFidelidade.PontosPorReal, e resgatados como desconto em uma venda (ver
+// entidades.Venda.ResgatarPontos). Os débitos consomem primeiro os pontos que vencem antes,
+// e os pontos vencidos sem uso são expirados ao consultar o saldo ou o extrato.
+// As alterações são serializadas por um mutex próprio.
+type DAOFidelidade struct {
+	mu  sync.Mutex                       // Serializa as alterações de pontos.
+	dao *DAO[*entidades.MovimentoPontos] // Histórico de movimentos.
+}
+
+var fidelidadeInstance *DAOFidelidade // Instância única do DAOFidelidade.
+var fidelidadeOnce sync.Once          // Garante a inicialização única do singleton.
+
+// GetFidelidadeInstance retorna a instância singleton de DAOFidelidade.
+// Os movimentos são gravados em log de escrita antecipada (movimentos_pontos.log e
+// movimentos_pontos.snapshot.json no diretório de dados).
+func GetFidelidadeInstance() *DAOFidelidade {
+	fidelidadeOnce.Do(func() {
+		storage := NewStorageLog[*entidades.MovimentoPontos](CaminhoDados("movimentos_pontos"), CompactacaoPadrao)
+		dao, err := NewDAOPersistente(storage)
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar os pontos de fidelidade: %v", err))
+		}
+		dao.AddIndex(indiceCliente, func(m *entidades.MovimentoPontos) string {
+			return strconv.FormatInt(m.ClienteID, 10)
+		})
+		fidelidadeInstance = &DAOFidelidade{dao: dao}
+	})
+	return fidelidadeInstance
+}
+
+// Saldo retorna o saldo de pontos do cliente, após expirar os pontos vencidos.
+func (d *DAOFidelidade) Saldo(clienteID int64) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.expirar(clienteID, time.Now()); err != nil {
+		return 0, err
+	}
+	return d.saldo(clienteID), nil
+}
+
+// Extrato retorna os movimentos de pontos do cliente, do mais recente ao mais antigo,
+// após expirar os pontos vencidos.
+func (d *DAOFidelidade) Extrato(clienteID int64) ([]*entidades.MovimentoPontos, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.expirar(clienteID, time.Now()); err != nil {
+		return nil, err
+	}
+	movimentos := d.movimentos(clienteID)
+	slices.Reverse(movimentos)
+	return movimentos, nil
+}
+
+// Consultar inicia uma consulta sobre os movimentos de pontos.
+func (d *DAOFidelidade) Consultar() *Consulta[*entidades.MovimentoPontos] {
+	return d.dao.Consultar()
+}
+
+// String retorna uma representação textual do histórico de movimentos.
+func (d *DAOFidelidade) String() string {
+	return d.dao.String()
+}
+
+// verificarResgate confere se o cliente da venda tem saldo para os pontos resgatados nela.
+// Retorna um *ErroPontosInsuficientes caso contrário. Deve ser chamado com d.mu obtido.
+func (d *DAOFidelidade) verificarResgate(venda *entidades.Venda) error {
+	if venda.PontosResgatados == 0 {
+		return nil
+	}
+	if err := d.expirar(venda.ClienteID, time.Now()); err != nil {
+		return err
+	}
+	if saldo := d.saldo(venda.ClienteID); saldo < venda.PontosResgatados {
+		return &ErroPontosInsuficientes{ClienteID: venda.ClienteID, Disponivel: saldo, Solicitado: venda.PontosResgatados}
+	}
+	return nil
+}
+
+// registrarVenda debita os pontos resgatados na venda finalizada e credita os pontos
+// ganhos sobre o seu total. Se um movimento falhar, os já registrados são desfeitos.
+// Deve ser chamado com d.mu obtido e após verificarResgate.
+func (d *DAOFidelidade) registrarVenda(venda *entidades.Venda) error {
+	if venda.ClienteID == 0 {
+		return nil
+	}
+	var registrados []*entidades.MovimentoPontos
+	if venda.PontosResgatados > 0 {
+		resgate, err := d.registrar(venda.ClienteID, entidades.PontosResgate, -venda.PontosResgatados, venda.GetID(),
+			time.Time{}, fmt.Sprintf("resgate na venda %d", venda.GetID()))
+		if err != nil {
+			return err
+		}
+		registrados = append(registrados, resgate)
+	}
+	programa := GetProgramaFidelidade()
+	if ganhos := programa.PontosGanhos(venda.Total()); ganhos > 0 {
+		agora := time.Now()
+		if _, err := d.registrar(venda.ClienteID, entidades.PontosAcumulo, ganhos, venda.GetID(),
+			programa.Validade(agora), fmt.Sprintf("venda %d", venda.GetID())); err != nil {
+			return desfeito(err, d.desfazer(registrados))
+		}
+	}
+	return nil
+}
+
+// estornarVenda reverte os pontos de uma venda cancelada ou estornada: os pontos resgatados
+// voltam ao cliente, com nova validade, e os ganhos são retirados, exceto os que já expiraram.
+// Se o cliente já gastou os pontos ganhos, é retirado apenas o saldo disponível.
+// Retorna os movimentos registrados, para que possam ser desfeitos (ver desfazer).
+// Deve ser chamado com d.mu obtido.
+func (d *DAOFidelidade) estornarVenda(venda *entidades.Venda, descricao string) ([]*entidades.MovimentoPontos, error) {
+	if venda.ClienteID == 0 {
+		return nil, nil
+	}
+	agora := time.Now()
+	if err := d.expirar(venda.ClienteID, agora); err != nil {
+		return nil, err
+	}
+	var resgatados, ganhos int64
+	for _, m := range d.movimentos(venda.ClienteID) {
+		if m.VendaID != venda.GetID() {
+			continue
+		}
+		switch m.Tipo {
+		case entidades.PontosEstorno:
+			return nil, nil // Pontos da venda já estornados.
+		case entidades.PontosResgate:
+			resgatados -= m.Pontos
+		case entidades.PontosAcumulo, entidades.PontosExpiracao:
+			ganhos += m.Pontos
+		}
+	}
+
+	var registrados []*entidades.MovimentoPontos
+	motivo := fmt.Sprintf("%s da venda %d", descricao, venda.GetID())
+	if resgatados > 0 {
+		credito, err := d.registrar(venda.ClienteID, entidades.PontosEstorno, resgatados, venda.GetID(),
+			GetProgramaFidelidade().Validade(agora), motivo)
+		if err != nil {
+			return nil, err
+		}
+		registrados = append(registrados, credito)
+	}
+	if debito := min(ganhos, d.saldo(venda.ClienteID)); debito > 0 {
+		m, err := d.registrar(venda.ClienteID, entidades.PontosEstorno, -debito, venda.GetID(), time.Time{}, motivo)
+		if err != nil {
+			return nil, desfeito(err, d.desfazer(registrados))
+		}
+		registrados = append(registrados, m)
+	}
+	return registrados, nil
+}
+
+// desfazer remove movimentos recém-registrados, quando a operação que os originou falha,
+// e retorna os erros das remoções que não puderam ser feitas.
+// Deve ser chamado com d.mu obtido.
+func (d *DAOFidelidade) desfazer(movimentos []*entidades.MovimentoPontos) error {
+	var erros []error
+	for _, m := range movimentos {
+		erros = append(erros, d.dao.Remover(m.GetID()))
+	}
+	return errors.Join(erros...)
+}
+
+// lotePontos é uma parcela dos pontos creditados a um cliente que ainda não foi consumida.
+type lotePontos struct {
+	vendaID  int64
+	restante int64
+	validade time.Time // Zero para pontos que não expiram.
+}
+
+// lotes reconstitui, a partir do extrato do cliente, os créditos ainda não consumidos.
+// Cada débito consome primeiro os créditos da mesma venda e, depois, os que vencem antes.
+// Deve ser chamado com d.mu obtido.
+func (d *DAOFidelidade) lotes(clienteID int64) []*lotePontos {
+	var lotes []*lotePontos
+	for _, m := range d.movimentos(clienteID) {
+		if m.Pontos > 0 {
+			lotes = append(lotes, &lotePontos{vendaID: m.VendaID, restante: m.Pontos, validade: m.Validade})
+			continue
+		}
+		ordem := slices.Clone(lotes)
+		slices.SortStableFunc(ordem, func(a, b *lotePontos) int {
+			if mesmaA, mesmaB := m.VendaID != 0 && a.vendaID == m.VendaID, m.VendaID != 0 && b.vendaID == m.VendaID; mesmaA != mesmaB {
+				if mesmaA {
+					return -1
+				}
+				return 1
+			}
+			switch {
+			case a.validade.Equal(b.validade):
+				return 0
+			case a.validade.IsZero():
+				return 1
+			case b.validade.IsZero():
+				return -1
+			}
+			return a.validade.Compare(b.validade)
+		})
+		debito := -m.Pontos
+		for _, lote := range ordem {
+			consumido := min(lote.restante, debito)
+			lote.restante -= consumido
+			debito -= consumido
+		}
+	}
+	return lotes
+}
+
+// expirar registra a expiração dos pontos do cliente vencidos até agora.
+// Deve ser chamado com d.mu obtido.
+func (d *DAOFidelidade) expirar(clienteID int64, agora time.Time) error {
+	for _, lote := range d.lotes(clienteID) {
+		if lote.restante <= 0 || lote.validade.IsZero() || lote.validade.After(agora) {
+			continue
+		}
+		motivo := "pontos vencidos em " + lote.validade.Format("02/01/2006")
+		if _, err := d.registrar(clienteID, entidades.PontosExpiracao, -lote.restante, lote.vendaID, time.Time{}, motivo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registrar grava um movimento de pontos do cliente com o saldo resultante.
+// Deve ser chamado com d.mu obtido.
+func (d *DAOFidelidade) registrar(clienteID int64, tipo entidades.TipoMovimentoPontos, pontos, vendaID int64, validade time.Time, motivo string) (*entidades.MovimentoPontos, error) {
+	movimento := entidades.NewMovimentoPontos(clienteID, tipo, pontos, motivo)
+	movimento.Saldo = d.saldo(clienteID) + pontos
+	movimento.VendaID = vendaID
+	movimento.Validade = validade
+	if err := d.dao.Adicionar(movimento); err != nil {
+		return nil, err
+	}
+	return movimento, nil
+}
+
+// saldo soma os pontos de todos os movimentos do cliente.
+func (d *DAOFidelidade) saldo(clienteID int64) int64 {
+	var total int64
+	for _, m := range d.movimentos(clienteID) {
+		total += m.Pontos
+	}
+	return total
+}
+
+// movimentos retorna os movimentos do cliente na ordem em que foram registrados.
+func (d *DAOFidelidade) movimentos(clienteID int64) []*entidades.MovimentoPontos {
+	return d.dao.BuscarPorIndice(indiceCliente, strconv.FormatInt(clienteID, 10))
+}
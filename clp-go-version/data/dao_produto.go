@@ -2,68 +2,182 @@ package data
 
 import (
 	"clp-go-version/entidades"
+	"fmt"
+	"strings"
 	"sync"
 )
 
 // DAOProduto é um singleton para gerenciar o DAO de Produto.
 // Ele encapsula o DAO genérico especializado para produtos e garante uma instância única.
+// A sincronização entre goroutines é feita pelo DAO genérico.
+// Os produtos são indexados pelo nome normalizado (ver NormalizarTexto), de modo que as
+// buscas por nome não diferenciam maiúsculas, minúsculas e acentos, e pelo SKU.
+// Nomes normalizados e SKUs são únicos: Adicionar e Atualizar retornam um *ErroUnicidade
+// para um produto que repita o nome ou o SKU de outro.
 type DAOProduto struct {
 	dao *DAO[*entidades.Produto] // DAO genérico para a entidade Produto.
 }
 
+// Nomes dos índices únicos de produtos.
+const (
+	indiceNome = "nome"
+	indiceSKU  = "sku"
+)
+
 var instance *DAOProduto // Instância única do singleton DAOProduto.
 var once sync.Once       // Garantia de inicialização única e thread-safe.
 
 // GetInstance retorna a instância singleton de DAOProduto.
-// A instância é inicializada apenas uma vez usando o sync.Once.
+// A instância é inicializada apenas uma vez usando o sync.Once, carregando os produtos
+// gravados em produtos.json no diretório de dados.
 func GetInstance() *DAOProduto {
 	once.Do(func() {
-		instance = &DAOProduto{
-			dao: NewDAO[*entidades.Produto](), // Criação do DAO especializado para Produto.
+		storage := NewStorageJSON[*entidades.Produto](CaminhoDados("produtos.json"))
+		dao, err := NewDAOPersistente(storage) // Criação do DAO especializado para Produto.
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar os produtos: %v", err))
 		}
+		instance = newDAOProduto(dao)
 	})
 	return instance
 }
 
+// newDAOProduto cria um DAOProduto sobre o DAO informado, com os índices de nome e SKU.
+func newDAOProduto(dao *DAO[*entidades.Produto]) *DAOProduto {
+	dao.AddUnique(indiceNome, func(p *entidades.Produto) string {
+		return NormalizarTexto(p.GetNome())
+	})
+	dao.AddUnique(indiceSKU, func(p *entidades.Produto) string {
+		return normalizarSKU(p.GetSKU())
+	})
+	return &DAOProduto{dao: dao}
+}
+
 // Adicionar adiciona um Produto ao DAO.
 // Este método encapsula a lógica de adição diretamente no DAO genérico.
-func (d *DAOProduto) Adicionar(produto *entidades.Produto) {
-	d.dao.Adicionar(produto)
+func (d *DAOProduto) Adicionar(produto *entidades.Produto) error {
+	return d.dao.Adicionar(produto)
+}
+
+// AdicionarComEstoque adiciona um Produto e registra o estoque inicial como uma entrada com
+// o motivo informado, para constar no histórico do estoque; com estoque zero, apenas
+// adiciona o produto. Se a entrada falhar, o produto é removido, para que o cadastro possa
+// ser repetido sem ser recusado como repetido. O produto informado não recebe o estoque:
+// busque-o novamente para obter o saldo gravado.
+func (d *DAOProduto) AdicionarComEstoque(produto *entidades.Produto, estoque int, motivo string) error {
+	if estoque < 0 {
+		return fmt.Errorf("%w: o estoque inicial não pode ser negativo", ErrInvalido)
+	}
+	if err := d.Adicionar(produto); err != nil {
+		return err
+	}
+	if estoque == 0 {
+		return nil
+	}
+	if _, err := GetEstoqueInstance().Entrada(produto.GetID(), estoque, motivo); err != nil {
+		return desfeito(err, d.Remover(produto.GetID()))
+	}
+	return nil
+}
+
+// AtualizarComEstoque grava as alterações do Produto, como Atualizar, e ajusta o estoque
+// para o saldo informado, registrando a diferença no histórico do estoque com o motivo
+// informado. Se o ajuste falhar, as alterações do produto são desfeitas, de modo que nada
+// muda. O produto informado não recebe o saldo: busque-o novamente para obtê-lo.
+func (d *DAOProduto) AtualizarComEstoque(produto *entidades.Produto, saldo int, motivo string) error {
+	if saldo < 0 {
+		return fmt.Errorf("%w: o estoque não pode ser negativo", ErrInvalido)
+	}
+	anterior, err := d.Buscar(produto.GetID())
+	if err != nil {
+		return err
+	}
+	alterado := *produto != *anterior
+	if alterado {
+		if err := d.Atualizar(produto); err != nil {
+			return err
+		}
+	}
+	if saldo == produto.GetEstoque() {
+		return nil
+	}
+	if _, err := GetEstoqueInstance().Ajustar(produto.GetID(), saldo, motivo); err != nil {
+		if !alterado {
+			return err
+		}
+		return desfeito(err, d.restaurar(anterior))
+	}
+	return nil
+}
+
+// restaurar grava novamente os dados do produto anterior, mantendo a versão e o estoque
+// atuais, que podem ter mudado desde a leitura.
+func (d *DAOProduto) restaurar(anterior *entidades.Produto) error {
+	atual, err := d.Buscar(anterior.GetID())
+	if err != nil {
+		return err
+	}
+	restaurado := anterior.Clonar()
+	restaurado.SetVersao(atual.GetVersao())
+	restaurado.SetEstoque(atual.GetEstoque())
+	return d.Atualizar(restaurado)
 }
 
 // Buscar por ID retorna um Produto com o ID especificado.
-// Realiza a busca no DAO genérico e retorna o ponteiro do produto correspondente.
-func (d *DAOProduto) Buscar(id int64) *entidades.Produto {
-	return *d.dao.Buscar(id) // Retorna o ponteiro desreferenciado do produto.
+// Realiza a busca no DAO genérico; retorna ErrNaoEncontrado se o produto não existir.
+func (d *DAOProduto) Buscar(id int64) (*entidades.Produto, error) {
+	return d.dao.Buscar(id)
 }
 
 // BuscarPorNome retorna um Produto com o nome especificado.
-// Realiza a busca iterando sobre os dados armazenados no DAO.
-func (d *DAOProduto) BuscarPorNome(nome string) *entidades.Produto {
-	for _, p := range d.dao.GetDados() {
-		if p.GetNome() == nome { // Compara o nome do produto.
-			return p
-		}
+// Realiza a busca no índice de nomes, sem diferenciar maiúsculas, minúsculas e acentos.
+// Retorna ErrNaoEncontrado caso não encontre.
+func (d *DAOProduto) BuscarPorNome(nome string) (*entidades.Produto, error) {
+	if encontrados := d.dao.BuscarPorIndice(indiceNome, NormalizarTexto(nome)); len(encontrados) > 0 {
+		return encontrados[0], nil
 	}
-	return nil // Retorna nil caso não encontre.
+	return nil, fmt.Errorf("%w: produto %q", ErrNaoEncontrado, nome)
+}
+
+// BuscarPorSKU retorna o Produto com o código informado, sem diferenciar maiúsculas e minúsculas.
+// Retorna ErrNaoEncontrado caso não encontre.
+func (d *DAOProduto) BuscarPorSKU(sku string) (*entidades.Produto, error) {
+	if encontrados := d.dao.BuscarPorIndice(indiceSKU, normalizarSKU(sku)); len(encontrados) > 0 {
+		return encontrados[0], nil
+	}
+	return nil, fmt.Errorf("%w: SKU %q", ErrNaoEncontrado, sku)
+}
+
+// Atualizar grava as alterações de um Produto existente.
+// Retorna um *ErroConflito se o produto foi alterado desde que foi lido.
+func (d *DAOProduto) Atualizar(produto *entidades.Produto) error {
+	return d.dao.Atualizar(produto)
 }
 
 // Remover por ID remove um Produto com o ID especificado.
-// Encapsula a lógica de remoção no DAO genérico.
-func (d *DAOProduto) Remover(id int64) {
-	d.dao.Remover(id)
-}
-
-// RemoverPorNome remove um Produto com o nome especificado.
-// Filtra os produtos e mantém apenas aqueles cujo nome não corresponde ao fornecido.
-func (d *DAOProduto) RemoverPorNome(nome string) {
-	filtrados := []*entidades.Produto{}
-	for _, p := range d.dao.GetDados() {
-		if p.GetNome() != nome { // Compara o nome para exclusão.
-			filtrados = append(filtrados, p)
-		}
+// Encapsula a lógica de remoção no DAO genérico; retorna ErrNaoEncontrado se o produto não existir.
+func (d *DAOProduto) Remover(id int64) error {
+	return d.dao.Remover(id)
+}
+
+// RemoverPorNome remove os Produtos com o nome especificado, com a mesma comparação de BuscarPorNome.
+// A busca e a remoção ocorrem em uma única operação do DAO genérico, sem que outra
+// goroutine altere os produtos entre uma e outra. Retorna ErrNaoEncontrado se nenhum
+// produto tiver esse nome.
+func (d *DAOProduto) RemoverPorNome(nome string) error {
+	normalizado := NormalizarTexto(nome)
+	removidos, err := d.dao.RemoverSe(func(p *entidades.Produto) bool {
+		return NormalizarTexto(p.GetNome()) == normalizado // Compara o nome para exclusão.
+	})
+	if err == nil && removidos == 0 {
+		return fmt.Errorf("%w: produto %q", ErrNaoEncontrado, nome)
 	}
-	d.dao.Dados = filtrados // Atualiza a lista de dados no DAO.
+	return err
+}
+
+// Consultar inicia uma consulta com filtros, ordenação e paginação sobre os produtos.
+func (d *DAOProduto) Consultar() *Consulta[*entidades.Produto] {
+	return d.dao.Consultar()
 }
 
 // String retorna uma representação textual do DAO de Produtos.
@@ -71,3 +185,9 @@ func (d *DAOProduto) RemoverPorNome(nome string) {
 func (d *DAOProduto) String() string {
 	return d.dao.String()
 }
+
+// normalizarSKU prepara um SKU para comparação, ignorando espaços nas pontas e
+// diferenças entre maiúsculas e minúsculas.
+func normalizarSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
@@ -0,0 +1,68 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DAOPromocao é um singleton que gerencia as promoções aplicadas às vendas.
+type DAOPromocao struct {
+	dao *DAO[*entidades.Promocao]
+}
+
+var promocaoInstance *DAOPromocao // Instância única do DAOPromocao.
+var promocaoOnce sync.Once        // Garante a inicialização única do singleton.
+
+// GetPromocaoInstance retorna a instância singleton de DAOPromocao.
+// As promoções são gravadas em promocoes.json no diretório de dados.
+func GetPromocaoInstance() *DAOPromocao {
+	promocaoOnce.Do(func() {
+		storage := NewStorageJSON[*entidades.Promocao](CaminhoDados("promocoes.json"))
+		dao, err := NewDAOPersistente(storage)
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar as promoções: %v", err))
+		}
+		promocaoInstance = &DAOPromocao{dao: dao}
+	})
+	return promocaoInstance
+}
+
+// Adicionar adiciona uma Promocao ao DAO.
+func (d *DAOPromocao) Adicionar(promocao *entidades.Promocao) error {
+	return d.dao.Adicionar(promocao)
+}
+
+// Buscar por ID retorna a Promocao com o ID especificado.
+// Retorna ErrNaoEncontrado se a promoção não existir.
+func (d *DAOPromocao) Buscar(id int64) (*entidades.Promocao, error) {
+	return d.dao.Buscar(id)
+}
+
+// Atualizar grava as alterações de uma Promocao existente.
+// Retorna um *ErroConflito se a promoção foi alterada desde que foi lida.
+func (d *DAOPromocao) Atualizar(promocao *entidades.Promocao) error {
+	return d.dao.Atualizar(promocao)
+}
+
+// Remover por ID remove a Promocao com o ID especificado.
+// As vendas já registradas mantêm os descontos que receberam.
+func (d *DAOPromocao) Remover(id int64) error {
+	return d.dao.Remover(id)
+}
+
+// Vigentes retorna as promoções ativas e dentro do horário no instante informado.
+func (d *DAOPromocao) Vigentes(agora time.Time) []*entidades.Promocao {
+	return d.dao.Consultar().Onde(func(p *entidades.Promocao) bool { return p.Vigente(agora) }).Listar()
+}
+
+// Consultar inicia uma consulta sobre as promoções.
+func (d *DAOPromocao) Consultar() *Consulta[*entidades.Promocao] {
+	return d.dao.Consultar()
+}
+
+// String retorna uma representação textual das promoções.
+func (d *DAOPromocao) String() string {
+	return d.dao.String()
+}
@@ -0,0 +1,114 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestDAOConcorrente adiciona, remove e busca produtos a partir de várias goroutines ao
+// mesmo tempo. Deve ser executado com -race para detectar acessos sem sincronização.
+func TestDAOConcorrente(t *testing.T) {
+	const goroutines, porGoroutine = 16, 200
+
+	dao := NewDAO[*entidades.Produto]()
+	dao.AddIndex("categoria", (*entidades.Produto).GetCategoria)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		ids       = map[int64]bool{} // ID -> se o produto deve permanecer no DAO.
+		repetidos []int64
+	)
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			categoria := fmt.Sprintf("categoria %d", g)
+			for i := range porGoroutine {
+				produto := entidades.NewProduto(fmt.Sprintf("Produto %d-%d", g, i), entidades.Reais(10))
+				produto.SetCategoria(categoria)
+				if err := dao.Adicionar(produto); err != nil {
+					t.Errorf("Adicionar: %v", err)
+					return
+				}
+				id := produto.GetID()
+				if _, err := dao.Buscar(id); err != nil {
+					t.Errorf("Buscar(%d) logo após adicionar: %v", id, err)
+				}
+				manter := i%2 == 0
+				if !manter {
+					if err := dao.Remover(id); err != nil {
+						t.Errorf("Remover(%d): %v", id, err)
+					}
+					if _, err := dao.Buscar(id); !errors.Is(err, ErrNaoEncontrado) {
+						t.Errorf("Buscar(%d) após remover: erro = %v, esperado ErrNaoEncontrado", id, err)
+					}
+				}
+				dao.BuscarPorIndice("categoria", categoria)
+				dao.GetDados()
+
+				mu.Lock()
+				if _, existe := ids[id]; existe {
+					repetidos = append(repetidos, id)
+				}
+				ids[id] = manter
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	if len(repetidos) > 0 {
+		t.Fatalf("IDs atribuídos mais de uma vez: %v", repetidos)
+	}
+	if len(ids) != goroutines*porGoroutine {
+		t.Fatalf("%d IDs atribuídos, esperado %d", len(ids), goroutines*porGoroutine)
+	}
+	esperados := goroutines * porGoroutine / 2
+	if n := len(dao.GetDados()); n != esperados {
+		t.Fatalf("o DAO terminou com %d produtos, esperado %d", n, esperados)
+	}
+	for id, manter := range ids {
+		_, err := dao.Buscar(id)
+		if manter && err != nil {
+			t.Errorf("Buscar(%d): %v", id, err)
+		}
+		if !manter && !errors.Is(err, ErrNaoEncontrado) {
+			t.Errorf("Buscar(%d) de produto removido: erro = %v", id, err)
+		}
+	}
+	for g := range goroutines {
+		if n := len(dao.BuscarPorIndice("categoria", fmt.Sprintf("categoria %d", g))); n != porGoroutine/2 {
+			t.Errorf("índice da categoria %d com %d produtos, esperado %d", g, n, porGoroutine/2)
+		}
+	}
+}
+
+// storageFalho é um Storage em que toda gravação falha.
+type storageFalho[E entidades.Entidade] struct{}
+
+func (storageFalho[E]) Carregar() ([]E, error)        { return nil, nil }
+func (storageFalho[E]) Gravar(Operacao[E], []E) error { return errors.New("disco cheio") }
+
+func TestAdicionarRestauraEntidadeQuandoGravacaoFalha(t *testing.T) {
+	dao, err := NewDAOPersistente[*entidades.Produto](storageFalho[*entidades.Produto]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	produto := entidades.NewProduto("Arroz", entidades.Reais(100))
+	if err := dao.Adicionar(produto); err == nil {
+		t.Fatal("Adicionar não retornou o erro da gravação")
+	}
+	if produto.GetID() != 0 || produto.GetVersao() != 0 {
+		t.Errorf("produto com ID %d e versão %d após a falha, esperado 0 e 0", produto.GetID(), produto.GetVersao())
+	}
+	if len(dao.GetDados()) != 0 {
+		t.Errorf("DAO com %d entidades após a falha, esperado nenhuma", len(dao.GetDados()))
+	}
+}
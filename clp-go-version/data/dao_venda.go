@@ -2,46 +2,323 @@ package data
 
 import (
 	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 	"sync"
+	"time"
 )
 
 // DAOVenda é um singleton para gerenciar o DAO de Venda.
 // Encapsula o DAO específico para a entidade Venda e garante que apenas uma instância seja criada.
+// A sincronização entre goroutines é feita pelo DAO genérico.
 type DAOVenda struct {
 	dao *DAO[*entidades.Venda] // Referência ao DAO genérico, especializado para vendas.
 }
 
+// Nomes dos índices de vendas.
+const (
+	indiceCliente = "cliente" // Vendas por cliente.
+	indiceCaixa   = "caixa"   // Vendas por sessão de caixa.
+)
+
 var vendaInstance *DAOVenda // Instância única do DAOVenda.
 var vendaOnce sync.Once     // Usado para garantir inicialização única e thread-safe do singleton.
 
 // GetVendaInstance retorna a instância singleton de DAOVenda.
 // Usa `sync.Once` para garantir que a inicialização ocorra apenas uma vez, mesmo em ambientes concorrentes.
+// As vendas são gravadas em um log de escrita antecipada (vendas.log e vendas.snapshot.json
+// no diretório de dados), para que uma venda confirmada sobreviva a uma queda de energia.
 func GetVendaInstance() *DAOVenda {
 	vendaOnce.Do(func() {
 		// Inicializa a instância única do DAOVenda.
-		vendaInstance = &DAOVenda{
-			dao: NewDAO[*entidades.Venda](), // Cria um novo DAO especializado para vendas.
+		storage := NewStorageLog[*entidades.Venda](CaminhoDados("vendas"), CompactacaoPadrao)
+		dao, err := NewDAOPersistente(storage) // Cria um novo DAO especializado para vendas.
+		if err != nil {
+			panic(fmt.Sprintf("não foi possível carregar as vendas: %v", err))
 		}
+		dao.AddIndex(indiceCliente, func(v *entidades.Venda) string {
+			return strconv.FormatInt(v.ClienteID, 10)
+		})
+		dao.AddIndex(indiceCaixa, func(v *entidades.Venda) string {
+			return strconv.FormatInt(v.CaixaID, 10)
+		})
+		vendaInstance = &DAOVenda{dao: dao}
 	})
 	return vendaInstance
 }
 
-// Adicionar adiciona uma Venda ao DAO.
-// Encapsula a funcionalidade de adicionar uma venda na estrutura de dados do DAO.
-func (d *DAOVenda) Adicionar(venda *entidades.Venda) {
-	d.dao.Adicionar(venda)
+// Adicionar adiciona uma Venda ao DAO, atribuindo-a ao caixa aberto, e dá baixa no estoque
+// dos seus itens. As promoções vigentes são reaplicadas a uma venda aberta antes da gravação.
+// Se não houver caixa aberto, retorna ErrCaixaFechado; se algum produto não tiver estoque
+// suficiente, um *ErroEstoqueInsuficiente; e se os descontos excederem o limite do operador,
+// um *ErroDescontoExcedido. Em todos esses casos a venda não é gravada. A verificação e a
+// baixa são feitas sem interrupção por outras operações de estoque.
+func (d *DAOVenda) Adicionar(venda *entidades.Venda) error {
+	estoque := GetEstoqueInstance()
+	estoque.mu.Lock()
+	defer estoque.mu.Unlock()
+	caixas := GetCaixaInstance()
+	caixas.mu.Lock()
+	defer caixas.mu.Unlock()
+
+	caixa, err := caixas.atual()
+	if err != nil {
+		return err
+	}
+	venda.CaixaID = caixa.GetID()
+
+	if agora := time.Now(); venda.Aberta() {
+		venda.AplicarPromocoes(GetPromocaoInstance().Vigentes(agora), agora)
+	}
+	if err := GetLimitesDesconto().Verificar(venda); err != nil {
+		return err
+	}
+	variacoes := variacoesVenda(nil, venda)
+	if err := estoque.verificarVenda(variacoes); err != nil {
+		return err
+	}
+	if err := d.dao.Adicionar(venda); err != nil {
+		return err
+	}
+	if err := estoque.aplicarVenda(venda.GetID(), variacoes, fmt.Sprintf("venda %d", venda.GetID())); err != nil {
+		return desfeito(err, d.dao.Remover(venda.GetID()))
+	}
+	return nil
+}
+
+// Previa retorna uma cópia da venda aberta com as promoções vigentes aplicadas, como ela
+// será gravada por Adicionar. Serve para calcular o restante a pagar antes do registro.
+func (d *DAOVenda) Previa(venda *entidades.Venda) *entidades.Venda {
+	copia, agora := venda.Clonar(), time.Now()
+	copia.AplicarPromocoes(GetPromocaoInstance().Vigentes(agora), agora)
+	return copia
+}
+
+// AdicionarFinalizada registra a venda, como Adicionar, e a finaliza com os pagamentos,
+// como Finalizar. Os pagamentos são conferidos sobre a Previa da venda antes de qualquer
+// gravação, e, se a finalização ainda assim falhar, a venda é removida, devolvendo os itens
+// ao estoque, para que o registro possa ser repetido sem deixar uma venda em aberto.
+func (d *DAOVenda) AdicionarFinalizada(venda *entidades.Venda, pagamentos []entidades.Pagamento) (*entidades.Venda, error) {
+	previa := d.Previa(venda)
+	previa.RemoverPagamentos()
+	for _, pagamento := range pagamentos {
+		if err := previa.AdicionarPagamento(pagamento); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalido, err)
+		}
+	}
+	if err := previa.Finalizar(); err != nil {
+		return nil, err
+	}
+
+	if err := d.Adicionar(venda); err != nil {
+		return nil, err
+	}
+	finalizada, err := d.Finalizar(venda.GetID(), pagamentos)
+	if err != nil {
+		if errRemover := d.Remover(venda.GetID()); errRemover != nil {
+			return nil, desfeito(fmt.Errorf("venda %d registrada em aberto, mas não finalizada: %w", venda.GetID(), err), errRemover)
+		}
+		return nil, err
+	}
+	return finalizada, nil
 }
 
 // Buscar por ID retorna uma Venda com o ID especificado.
 // Realiza a busca no DAO e retorna a referência da venda correspondente.
-func (d *DAOVenda) Buscar(id int64) *entidades.Venda {
-	return *d.dao.Buscar(id) // Retorna o ponteiro desreferenciado da venda encontrada.
+// Retorna ErrNaoEncontrado se a venda não existir.
+func (d *DAOVenda) Buscar(id int64) (*entidades.Venda, error) {
+	return d.dao.Buscar(id)
+}
+
+// Atualizar grava as alterações nos itens de uma Venda aberta e acerta o estoque pela
+// diferença entre os itens anteriores e os novos.
+// Retorna ErrVendaEncerrada se a venda não estiver aberta, um *ErroConflito se a venda foi
+// alterada desde que foi lida e um *ErroEstoqueInsuficiente se não houver estoque para os
+// novos itens. O status só muda por Finalizar, Cancelar e Estornar. As promoções vigentes
+// são reaplicadas antes da gravação.
+func (d *DAOVenda) Atualizar(venda *entidades.Venda) error {
+	estoque := GetEstoqueInstance()
+	estoque.mu.Lock()
+	defer estoque.mu.Unlock()
+
+	anterior, err := d.dao.Buscar(venda.GetID())
+	if err != nil {
+		return err
+	}
+	if !anterior.Aberta() || !venda.Aberta() {
+		return ErrVendaEncerrada
+	}
+	agora := time.Now()
+	venda.AplicarPromocoes(GetPromocaoInstance().Vigentes(agora), agora)
+	if err := GetLimitesDesconto().Verificar(venda); err != nil {
+		return err
+	}
+	variacoes := variacoesVenda(anterior, venda)
+	if err := estoque.verificarVenda(variacoes); err != nil {
+		return err
+	}
+	if err := d.dao.Atualizar(venda); err != nil {
+		return err
+	}
+	if err := estoque.aplicarVenda(venda.GetID(), variacoes, fmt.Sprintf("alteração da venda %d", venda.GetID())); err != nil {
+		restaurada := anterior.Clonar()
+		restaurada.SetVersao(venda.GetVersao())
+		return desfeito(err, d.dao.Atualizar(restaurada))
+	}
+	return nil
+}
+
+// Remover por ID descarta uma Venda aberta e devolve seus itens ao estoque.
+// Retorna ErrNaoEncontrado se a venda não existir e ErrVendaEncerrada se ela não estiver
+// aberta: vendas finalizadas permanecem no histórico e devem ser canceladas ou estornadas.
+func (d *DAOVenda) Remover(id int64) error {
+	estoque := GetEstoqueInstance()
+	estoque.mu.Lock()
+	defer estoque.mu.Unlock()
+
+	venda, err := d.dao.Buscar(id)
+	if err != nil {
+		return err
+	}
+	if !venda.Aberta() {
+		return ErrVendaEncerrada
+	}
+	// O estoque é devolvido antes da remoção, para que uma falha em qualquer dos passos
+	// deixe a venda e as suas saídas de estoque como estavam.
+	motivo := fmt.Sprintf("remoção da venda %d", id)
+	if err := estoque.aplicarVenda(id, variacoesVenda(venda, nil), motivo); err != nil {
+		return err
+	}
+	if err := d.dao.Remover(id); err != nil {
+		return desfeito(err, estoque.aplicarVenda(id, variacoesVenda(nil, venda), "estorno da "+motivo))
+	}
+	return nil
+}
+
+// Finalizar conclui a Venda aberta com o ID especificado, pagos com os pagamentos
+// informados, calcula os seus tributos pela regra tributária em uso (ver GetRegraTributaria)
+// e retorna a venda atualizada. A venda passa a pertencer ao caixa aberto, que recebe os
+// pagamentos. Se a venda tiver cliente, debita os pontos resgatados e credita os pontos
+// ganhos (ver DAOFidelidade).
+// Retorna ErrCaixaFechado se não houver caixa aberto, um *entidades.ErroTransicao se a
+// venda não estiver aberta, um erro equivalente a
+// entidades.ErrPagamentoInsuficiente se os pagamentos não cobrirem o total e um
+// *ErroPontosInsuficientes se o cliente não tiver os pontos resgatados.
+func (d *DAOVenda) Finalizar(id int64, pagamentos []entidades.Pagamento) (*entidades.Venda, error) {
+	caixas := GetCaixaInstance()
+	caixas.mu.Lock()
+	defer caixas.mu.Unlock()
+	fidelidade := GetFidelidadeInstance()
+	fidelidade.mu.Lock()
+	defer fidelidade.mu.Unlock()
+
+	caixa, err := caixas.atual()
+	if err != nil {
+		return nil, err
+	}
+	anterior, err := d.dao.Buscar(id)
+	if err != nil {
+		return nil, err
+	}
+	venda := anterior.Clonar()
+	venda.CaixaID = caixa.GetID()
+	venda.Pagamentos = slices.Clone(pagamentos)
+	if err := venda.Finalizar(); err != nil {
+		return nil, err
+	}
+	venda.CalcularTributos(GetRegraTributaria())
+	if err := fidelidade.verificarResgate(venda); err != nil {
+		return nil, err
+	}
+	if err := d.dao.Atualizar(venda); err != nil {
+		return nil, err
+	}
+	if err := fidelidade.registrarVenda(venda); err != nil {
+		restaurada := anterior.Clonar()
+		restaurada.SetVersao(venda.GetVersao())
+		return nil, desfeito(err, d.dao.Atualizar(restaurada))
+	}
+	return venda, nil
+}
+
+// Cancelar anula a Venda com o ID especificado e devolve seus itens ao estoque.
+// A venda é mantida no histórico com o motivo e a data do cancelamento.
+func (d *DAOVenda) Cancelar(id int64, motivo string) (*entidades.Venda, error) {
+	return d.encerrar(id, func(v *entidades.Venda) error { return v.Cancelar(motivo) }, "cancelamento")
+}
+
+// Estornar desfaz a Venda finalizada com o ID especificado e devolve seus itens ao estoque.
+// A venda é mantida no histórico com o motivo e a data do estorno.
+func (d *DAOVenda) Estornar(id int64, motivo string) (*entidades.Venda, error) {
+	return d.encerrar(id, func(v *entidades.Venda) error { return v.Estornar(motivo) }, "estorno")
+}
+
+// encerrar aplica à venda a transição informada, grava-a, reverte os pontos de fidelidade
+// da venda e devolve os itens ao estoque, exceto as quantidades que já voltaram ao estoque
+// por devoluções. Se a reversão dos pontos ou a devolução ao estoque falhar, a venda volta
+// ao status anterior.
+func (d *DAOVenda) encerrar(id int64, transicao func(*entidades.Venda) error, descricao string) (*entidades.Venda, error) {
+	estoque := GetEstoqueInstance()
+	estoque.mu.Lock()
+	defer estoque.mu.Unlock()
+	fidelidade := GetFidelidadeInstance()
+	fidelidade.mu.Lock()
+	defer fidelidade.mu.Unlock()
+
+	anterior, err := d.dao.Buscar(id)
+	if err != nil {
+		return nil, err
+	}
+	venda := anterior.Clonar()
+	if err := transicao(venda); err != nil {
+		return nil, err
+	}
+	if err := d.dao.Atualizar(venda); err != nil {
+		return nil, err
+	}
+	restaurar := func() error {
+		restaurada := anterior.Clonar()
+		restaurada.SetVersao(venda.GetVersao())
+		return d.dao.Atualizar(restaurada)
+	}
+	pontos, err := fidelidade.estornarVenda(venda, descricao)
+	if err != nil {
+		return nil, desfeito(err, restaurar())
+	}
+	variacoes := variacoesVenda(venda, nil)
+	for _, devolucao := range GetDevolucaoInstance().PorVenda(id) {
+		for _, item := range devolucao.GetItens() {
+			variacoes[item.ProdutoID] -= item.Quantidade
+		}
+	}
+	if err := estoque.aplicarVenda(id, variacoes, fmt.Sprintf("%s da venda %d", descricao, id)); err != nil {
+		return nil, desfeito(err, errors.Join(fidelidade.desfazer(pontos), restaurar()))
+	}
+	return venda, nil
+}
+
+// Faturamento soma o total das vendas finalizadas que atendem à condição informada,
+// ignorando as abertas, as canceladas e as estornadas. Com condição nil, soma todas.
+func (d *DAOVenda) Faturamento(condicao func(*entidades.Venda) bool) entidades.Dinheiro {
+	consulta := d.dao.Consultar()
+	if condicao != nil {
+		consulta.Onde(condicao)
+	}
+	return entidades.SomarVendas(consulta.Listar())
+}
+
+// PorCliente retorna as vendas do cliente informado, das mais recentes às mais antigas.
+func (d *DAOVenda) PorCliente(clienteID int64) []*entidades.Venda {
+	vendas := d.dao.BuscarPorIndice(indiceCliente, strconv.FormatInt(clienteID, 10))
+	slices.SortFunc(vendas, Decrescente(PorChave(func(v *entidades.Venda) int64 { return v.GetDataHora().UnixNano() })).Comparar)
+	return vendas
 }
 
-// Remover por ID remove uma Venda com o ID especificado.
-// Encapsula a funcionalidade de remoção de vendas no DAO.
-func (d *DAOVenda) Remover(id int64) {
-	d.dao.Remover(id)
+// Consultar inicia uma consulta com filtros, ordenação e paginação sobre as vendas.
+func (d *DAOVenda) Consultar() *Consulta[*entidades.Venda] {
+	return d.dao.Consultar()
 }
 
 // String retorna uma representação textual do DAO de Vendas.
@@ -0,0 +1,142 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+)
+
+// Erros retornados pelo pacote data. Os erros retornados podem conter detalhes
+// adicionais, portanto devem ser comparados com errors.Is.
+var (
+	// ErrNaoEncontrado indica que não existe entidade com o identificador ou a chave informada.
+	ErrNaoEncontrado = errors.New("registro não encontrado")
+
+	// ErrDuplicado indica uma entidade que já existe no DAO.
+	ErrDuplicado = errors.New("registro duplicado")
+
+	// ErrInvalido indica uma entidade rejeitada pela sua validação (ver entidades.Validavel).
+	ErrInvalido = errors.New("registro inválido")
+
+	// ErrEstoqueInsuficiente indica uma saída maior que o saldo em estoque do produto.
+	ErrEstoqueInsuficiente = errors.New("estoque insuficiente")
+
+	// ErrVendaEncerrada indica uma alteração de itens ou remoção de uma venda que não está aberta.
+	ErrVendaEncerrada = errors.New("a venda não está aberta")
+
+	// ErrDescontoExcedido indica um desconto acima do limite do operador (ver LimitesDesconto).
+	ErrDescontoExcedido = errors.New("desconto acima do permitido")
+
+	// ErrPontosInsuficientes indica um resgate maior que o saldo de pontos do cliente.
+	ErrPontosInsuficientes = errors.New("pontos insuficientes")
+
+	// ErrCaixaFechado indica uma operação que exige um caixa aberto quando não há nenhum.
+	ErrCaixaFechado = errors.New("nenhum caixa aberto")
+
+	// ErrLogCorrompido indica um arquivo de log (ver StorageLog) com um registro danificado no
+	// meio, que não pode ser descartado como gravação interrompida sem perder os registros
+	// seguintes. O arquivo é mantido como está para recuperação manual.
+	ErrLogCorrompido = errors.New("log corrompido")
+
+	// ErrCaixaAberto indica a abertura de um caixa quando já existe outro aberto.
+	ErrCaixaAberto = errors.New("já existe um caixa aberto")
+)
+
+// ErroConflito indica uma atualização feita a partir de uma versão desatualizada da entidade,
+// isto é, outra operação a alterou depois que ela foi lida.
+type ErroConflito struct {
+	ID             int64 // ID da entidade.
+	VersaoEsperada int64 // Versão lida por quem tentou atualizar.
+	VersaoAtual    int64 // Versão armazenada no DAO.
+}
+
+// Error implementa a interface error.
+func (e *ErroConflito) Error() string {
+	return fmt.Sprintf("conflito de versão no registro %d: esperada %d, atual %d", e.ID, e.VersaoEsperada, e.VersaoAtual)
+}
+
+// ErroUnicidade indica uma entidade que viola uma restrição de unicidade do DAO (ver DAO.AddUnique).
+// É equivalente a ErrDuplicado para errors.Is.
+type ErroUnicidade struct {
+	Restricao string // Nome da restrição violada, como "nome" ou "sku".
+	Chave     string // Valor repetido.
+}
+
+// Error implementa a interface error.
+func (e *ErroUnicidade) Error() string {
+	return fmt.Sprintf("%v: já existe um registro com %s %q", ErrDuplicado, e.Restricao, e.Chave)
+}
+
+// Is faz com que errors.Is(err, ErrDuplicado) seja verdadeiro para um *ErroUnicidade.
+func (e *ErroUnicidade) Is(alvo error) bool {
+	return alvo == ErrDuplicado
+}
+
+// ErroEstoqueInsuficiente detalha uma saída de estoque recusada por falta de saldo.
+// É equivalente a ErrEstoqueInsuficiente para errors.Is.
+type ErroEstoqueInsuficiente struct {
+	ProdutoID  int64
+	Produto    string // Nome do produto.
+	Disponivel int    // Saldo em estoque.
+	Solicitado int    // Quantidade que se tentou retirar.
+}
+
+// Error implementa a interface error.
+func (e *ErroEstoqueInsuficiente) Error() string {
+	return fmt.Sprintf("%v de %s: disponível %d, solicitado %d", ErrEstoqueInsuficiente, e.Produto, e.Disponivel, e.Solicitado)
+}
+
+// Is faz com que errors.Is(err, ErrEstoqueInsuficiente) seja verdadeiro para um *ErroEstoqueInsuficiente.
+func (e *ErroEstoqueInsuficiente) Is(alvo error) bool {
+	return alvo == ErrEstoqueInsuficiente
+}
+
+// ErroDescontoExcedido indica uma venda com desconto acima do permitido ao operador.
+// É equivalente a ErrDescontoExcedido para errors.Is.
+type ErroDescontoExcedido struct {
+	Operador string
+	Maximo   entidades.Percentual // Desconto máximo do operador.
+	Aplicado entidades.Percentual // Desconto concedido na venda sobre o valor promocional.
+}
+
+// Error implementa a interface error.
+func (e *ErroDescontoExcedido) Error() string {
+	operador := e.Operador
+	if operador == "" {
+		operador = "sem operador"
+	}
+	return fmt.Sprintf("%v: %s aplicado, máximo de %s para %s", ErrDescontoExcedido, e.Aplicado, e.Maximo, operador)
+}
+
+// Is faz com que errors.Is(err, ErrDescontoExcedido) seja verdadeiro para um *ErroDescontoExcedido.
+func (e *ErroDescontoExcedido) Is(alvo error) bool {
+	return alvo == ErrDescontoExcedido
+}
+
+// ErroPontosInsuficientes detalha um resgate de pontos recusado por falta de saldo.
+// É equivalente a ErrPontosInsuficientes para errors.Is.
+type ErroPontosInsuficientes struct {
+	ClienteID  int64
+	Disponivel int64 // Saldo de pontos do cliente.
+	Solicitado int64 // Pontos que se tentou resgatar.
+}
+
+// Error implementa a interface error.
+func (e *ErroPontosInsuficientes) Error() string {
+	return fmt.Sprintf("%v do cliente %d: disponível %d, solicitado %d", ErrPontosInsuficientes, e.ClienteID, e.Disponivel, e.Solicitado)
+}
+
+// Is faz com que errors.Is(err, ErrPontosInsuficientes) seja verdadeiro para um *ErroPontosInsuficientes.
+func (e *ErroPontosInsuficientes) Is(alvo error) bool {
+	return alvo == ErrPontosInsuficientes
+}
+
+// desfeito combina err, o erro que interrompeu uma operação, com o erro ocorrido ao
+// desfazer o que ela já havia gravado. Se o desfazimento funcionou, retorna apenas err;
+// caso contrário, o erro retornado corresponde aos dois em errors.Is e errors.As.
+func desfeito(err, errDesfazer error) error {
+	if errDesfazer == nil {
+		return err
+	}
+	return errors.Join(err, fmt.Errorf("a operação não pôde ser desfeita: %w", errDesfazer))
+}
@@ -0,0 +1,161 @@
+package data
+
+import (
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IDGenerator gera os identificadores atribuídos pelo DAO às entidades adicionadas.
+type IDGenerator interface {
+	// ProximoID retorna um novo identificador, maior que zero.
+	ProximoID() int64
+
+	// Observar informa um identificador já existente no DAO, para que o gerador
+	// não volte a emiti-lo. É chamado para cada entidade carregada do Storage.
+	Observar(id int64)
+}
+
+// GeradorSequencial gera identificadores 1, 2, 3... a partir do maior ID observado.
+type GeradorSequencial struct {
+	mu     sync.Mutex
+	ultimo int64
+}
+
+// NewGeradorSequencial cria um GeradorSequencial que começa em 1.
+func NewGeradorSequencial() *GeradorSequencial {
+	return &GeradorSequencial{}
+}
+
+// ProximoID retorna o identificador seguinte ao último emitido ou observado.
+func (g *GeradorSequencial) ProximoID() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ultimo++
+	return g.ultimo
+}
+
+// Observar garante que os próximos identificadores sejam maiores que id.
+func (g *GeradorSequencial) Observar(id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ultimo = max(g.ultimo, id)
+}
+
+// epocaSnowflake é o instante zero dos identificadores Snowflake (2024-01-01 UTC).
+var epocaSnowflake = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+const (
+	bitsNoSnowflake        = 10
+	bitsSequenciaSnowflake = 12
+	maxSequenciaSnowflake  = 1<<bitsSequenciaSnowflake - 1
+)
+
+// GeradorSnowflake gera identificadores no formato Snowflake:
+// 41 bits de milissegundos desde epocaSnowflake, 10 bits do número do nó e 12 bits de sequência.
+// Cada nó gera até 4096 identificadores por milissegundo sem coordenação com os demais,
+// e os identificadores são crescentes mesmo que o relógio retroceda.
+type GeradorSnowflake struct {
+	mu        sync.Mutex
+	no        int64
+	ms        int64 // Milissegundo do último identificador.
+	sequencia int64 // Sequência do último identificador dentro de ms.
+	relogio   func() time.Time
+}
+
+// NewGeradorSnowflake cria um GeradorSnowflake para o nó informado (0 a 1023).
+func NewGeradorSnowflake(no int64) *GeradorSnowflake {
+	return &GeradorSnowflake{no: no & (1<<bitsNoSnowflake - 1), relogio: time.Now}
+}
+
+// ProximoID retorna um novo identificador Snowflake.
+func (g *GeradorSnowflake) ProximoID() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	agora := g.relogio().UnixMilli() - epocaSnowflake
+	switch {
+	case agora > g.ms:
+		g.ms, g.sequencia = agora, 0
+	case g.sequencia < maxSequenciaSnowflake:
+		g.sequencia++
+	default:
+		// Sequência esgotada (ou relógio atrasado): avança para o próximo milissegundo.
+		g.ms, g.sequencia = g.ms+1, 0
+	}
+	return g.ms<<(bitsNoSnowflake+bitsSequenciaSnowflake) | g.no<<bitsSequenciaSnowflake | g.sequencia
+}
+
+// Observar garante que os próximos identificadores sejam maiores que id.
+func (g *GeradorSnowflake) Observar(id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ms := id >> (bitsNoSnowflake + bitsSequenciaSnowflake)
+	sequencia := id & maxSequenciaSnowflake
+	if ms > g.ms || ms == g.ms && sequencia > g.sequencia {
+		g.ms, g.sequencia = ms, sequencia
+	}
+}
+
+const (
+	bitsAleatoriosULID = 15
+	maxAleatorioULID   = 1<<bitsAleatoriosULID - 1
+)
+
+// GeradorULID gera identificadores no estilo ULID adaptados a 63 bits:
+// 48 bits de milissegundos Unix seguidos de 15 bits aleatórios. Identificadores
+// gerados no mesmo milissegundo incrementam a parte aleatória, mantendo a ordenação
+// monotônica como na especificação ULID. A forma textual é obtida com FormatarULID.
+type GeradorULID struct {
+	mu        sync.Mutex
+	ms        int64 // Milissegundo do último identificador.
+	aleatorio int64 // Parte aleatória do último identificador.
+	relogio   func() time.Time
+}
+
+// NewGeradorULID cria um GeradorULID.
+func NewGeradorULID() *GeradorULID {
+	return &GeradorULID{relogio: time.Now}
+}
+
+// ProximoID retorna um novo identificador ULID de 63 bits.
+func (g *GeradorULID) ProximoID() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	agora := g.relogio().UnixMilli()
+	switch {
+	case agora > g.ms:
+		// Metade do espaço aleatório fica livre para os incrementos no mesmo milissegundo.
+		g.ms, g.aleatorio = agora, rand.Int64N(maxAleatorioULID/2)
+	case g.aleatorio < maxAleatorioULID:
+		g.aleatorio++
+	default:
+		g.ms, g.aleatorio = g.ms+1, 0
+	}
+	return g.ms<<bitsAleatoriosULID | g.aleatorio
+}
+
+// Observar garante que os próximos identificadores sejam maiores que id.
+func (g *GeradorULID) Observar(id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ms, aleatorio := id>>bitsAleatoriosULID, id&maxAleatorioULID
+	if ms > g.ms || ms == g.ms && aleatorio > g.aleatorio {
+		g.ms, g.aleatorio = ms, aleatorio
+	}
+}
+
+// alfabetoCrockford é o alfabeto Base32 de Crockford usado pelos ULIDs.
+const alfabetoCrockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// FormatarULID retorna a representação textual (Base32 de Crockford, 13 caracteres)
+// de um identificador gerado por GeradorULID. A ordem textual acompanha a numérica.
+func FormatarULID(id int64) string {
+	var sb strings.Builder
+	for deslocamento := 60; deslocamento >= 0; deslocamento -= 5 {
+		sb.WriteByte(alfabetoCrockford[(uint64(id)>>deslocamento)&31])
+	}
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"slices"
+)
+
+// indice é um índice secundário em memória: agrupa as entidades pela chave calculada
+// por uma função, permitindo buscas por igualdade em tempo constante.
+// A chave de cada entidade é guardada no momento da inserção, para que a remoção
+// encontre a entrada correta mesmo que a entidade tenha sido alterada depois.
+// Um índice único não admite duas entidades com a mesma chave não vazia.
+type indice[E entidades.Entidade] struct {
+	chave    func(E) string
+	unico    bool
+	entradas map[string][]E
+	chaves   map[int64]string // Chave com que cada ID foi indexado.
+}
+
+// newIndice cria um índice e o preenche com as entidades informadas.
+func newIndice[E entidades.Entidade](chave func(E) string, unico bool, dados []E) *indice[E] {
+	idx := &indice[E]{chave: chave, unico: unico, entradas: map[string][]E{}, chaves: map[int64]string{}}
+	for _, e := range dados {
+		idx.adicionar(e)
+	}
+	return idx
+}
+
+// adicionar inclui a entidade na entrada da sua chave.
+func (idx *indice[E]) adicionar(e E) {
+	k := idx.chave(e)
+	idx.entradas[k] = append(idx.entradas[k], e)
+	idx.chaves[e.GetID()] = k
+}
+
+// remover retira do índice a entidade com o ID informado.
+func (idx *indice[E]) remover(id int64) {
+	k, ok := idx.chaves[id]
+	if !ok {
+		return
+	}
+	delete(idx.chaves, id)
+
+	restantes := slices.DeleteFunc(idx.entradas[k], func(e E) bool {
+		return e.GetID() == id
+	})
+	if len(restantes) == 0 {
+		delete(idx.entradas, k)
+		return
+	}
+	idx.entradas[k] = restantes
+}
+
+// conflito informa a chave de e se ela já pertencer a outra entidade em um índice único.
+func (idx *indice[E]) conflito(e E) (string, bool) {
+	if !idx.unico {
+		return "", false
+	}
+	k := idx.chave(e)
+	if k == "" {
+		return "", false
+	}
+	for _, outro := range idx.entradas[k] {
+		if outro.GetID() != e.GetID() {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// buscar retorna uma cópia das entidades com a chave informada.
+func (idx *indice[E]) buscar(k string) []E {
+	return slices.Clone(idx.entradas[k])
+}
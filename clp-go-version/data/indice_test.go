@@ -0,0 +1,68 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"testing"
+)
+
+// BenchmarkBuscarPorIndice mede as buscas do DAOProduto por nome, por SKU e por ID, que
+// usam índices hash, e as compara com a busca linear pelo nome, como era feita antes dos
+// índices, para catálogos de tamanhos diferentes.
+func BenchmarkBuscarPorIndice(b *testing.B) {
+	for _, tamanho := range []int{1e2, 1e4, 1e6} {
+		dao := newDAOProduto(NewDAO[*entidades.Produto]())
+		for i := range tamanho {
+			produto := entidades.NewProduto(fmt.Sprintf("Produto %d", i), entidades.Reais(100))
+			produto.SetSKU(fmt.Sprintf("SKU-%07d", i))
+			if err := dao.Adicionar(produto); err != nil {
+				b.Fatal(err)
+			}
+		}
+		// O último produto é o pior caso da busca linear.
+		nome, sku, id := fmt.Sprintf("produto %d", tamanho-1), fmt.Sprintf("sku-%07d", tamanho-1), int64(tamanho)
+
+		b.Run(fmt.Sprintf("nome/%d", tamanho), func(b *testing.B) {
+			for range b.N {
+				if _, err := dao.BuscarPorNome(nome); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("sku/%d", tamanho), func(b *testing.B) {
+			for range b.N {
+				if _, err := dao.BuscarPorSKU(sku); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("id/%d", tamanho), func(b *testing.B) {
+			for range b.N {
+				if _, err := dao.Buscar(id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("linear/%d", tamanho), func(b *testing.B) {
+			for range b.N {
+				if buscarLinear(dao.dao, nome) == nil {
+					b.Fatal("produto não encontrado")
+				}
+			}
+		})
+	}
+}
+
+// buscarLinear percorre todos os produtos do DAO comparando o nome normalizado, como era
+// feito antes dos índices.
+func buscarLinear(dao *DAO[*entidades.Produto], nome string) *entidades.Produto {
+	dao.mu.RLock()
+	defer dao.mu.RUnlock()
+	nome = NormalizarTexto(nome)
+	for _, p := range dao.dados {
+		if NormalizarTexto(p.GetNome()) == nome {
+			return p
+		}
+	}
+	return nil
+}
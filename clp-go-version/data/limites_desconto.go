@@ -0,0 +1,81 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"sync"
+)
+
+// LimitePadraoDesconto é o desconto máximo dos operadores sem limite configurado
+// quando o arquivo de limites não existe.
+const LimitePadraoDesconto entidades.Percentual = 1000 // 10%
+
+// LimitesDesconto define o desconto máximo que cada operador pode conceder em uma venda,
+// medido sobre o valor já com as promoções (ver entidades.Venda.PercentualDesconto).
+// Os descontos das promoções não contam para o limite.
+//
+// Os limites são lidos do arquivo limites_desconto.json no diretório de dados, no formato
+//
+//	{"Padrao": 1000, "Operadores": {"maria": 2500}}
+//
+// com os percentuais em centésimos de ponto (2500 = 25%). Os nomes dos operadores são
+// comparados sem diferenciar maiúsculas e acentos.
+type LimitesDesconto struct {
+	Padrao     entidades.Percentual            // Limite dos operadores não listados.
+	Operadores map[string]entidades.Percentual // Limite de cada operador.
+}
+
+var limitesInstance *LimitesDesconto // Instância única dos limites.
+var limitesOnce sync.Once            // Garante a leitura única do arquivo.
+
+// GetLimitesDesconto retorna os limites de desconto configurados.
+func GetLimitesDesconto() *LimitesDesconto {
+	limitesOnce.Do(func() {
+		limites := &LimitesDesconto{Padrao: LimitePadraoDesconto}
+		if err := lerJSON(CaminhoDados("limites_desconto.json"), limites); err != nil {
+			panic(fmt.Sprintf("não foi possível carregar os limites de desconto: %v", err))
+		}
+		normalizados := map[string]entidades.Percentual{}
+		for operador, limite := range limites.Operadores {
+			normalizados[NormalizarTexto(operador)] = limite
+		}
+		limites.Operadores = normalizados
+		limitesInstance = limites
+	})
+	return limitesInstance
+}
+
+// Maximo retorna o desconto máximo permitido ao operador.
+func (l *LimitesDesconto) Maximo(operador string) entidades.Percentual {
+	if limite, ok := l.Operadores[NormalizarTexto(operador)]; ok {
+		return limite
+	}
+	return l.Padrao
+}
+
+// Verificar confere se o desconto total da venda está dentro do limite do seu operador.
+// Retorna um *ErroDescontoExcedido caso contrário.
+//
+// Como cada desconto é arredondado para centavos, um desconto igual ao limite pode
+// ultrapassá-lo em até meio centavo; essa diferença é tolerada para cada desconto da venda.
+func (l *LimitesDesconto) Verificar(venda *entidades.Venda) error {
+	maximo := l.Maximo(venda.Operador)
+	arredondamentos := int64(0)
+	if !venda.Desconto.Vazio() {
+		arredondamentos++
+	}
+	for _, item := range venda.GetItens() {
+		if !item.Desconto.Vazio() {
+			arredondamentos++
+		}
+	}
+
+	// Em meios centavos: desconto ≤ valor promocional × máximo + meio centavo por arredondamento.
+	escala := int64(entidades.CemPorCento)
+	desconto := 2 * venda.DescontoManual().Centavos * escala
+	permitido := 2*venda.ValorPromocional().Centavos*int64(maximo) + arredondamentos*escala
+	if desconto > permitido {
+		return &ErroDescontoExcedido{Operador: venda.Operador, Maximo: maximo, Aplicado: venda.PercentualDesconto()}
+	}
+	return nil
+}
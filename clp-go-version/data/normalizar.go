@@ -0,0 +1,30 @@
+package data
+
+import (
+	"strings"
+	"unicode"
+)
+
+// semAcento mapeia as letras acentuadas usadas em português para a letra sem acento.
+var semAcento = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// NormalizarTexto prepara um texto para comparação: converte para minúsculas, remove
+// acentos e espaços excedentes. Assim "Feijão  Preto" e "feijao preto" são equivalentes.
+func NormalizarTexto(texto string) string {
+	var sb strings.Builder
+	for _, r := range strings.Join(strings.Fields(texto), " ") {
+		r = unicode.ToLower(r)
+		if base, ok := semAcento[r]; ok {
+			r = base
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ProgramaFidelidade define as regras do programa de pontos dos clientes.
+//
+// É lido do arquivo fidelidade.json no diretório de dados, no formato
+//
+//	{"PontosPorReal": 1, "ValorPonto": 0.01, "ValidadeDias": 365}
+//
+// Sem o arquivo, cada real gasto vale um ponto, cada ponto vale um centavo no resgate
+// e os pontos expiram em um ano. ValorPonto é informado em reais. Com ValidadeDias igual
+// a zero, os pontos não expiram.
+type ProgramaFidelidade struct {
+	PontosPorReal int64              // Pontos ganhos por real do total da venda.
+	ValorPonto    entidades.Dinheiro // Valor de cada ponto no resgate.
+	ValidadeDias  int                // Dias até os pontos ganhos expirarem.
+}
+
+var programaInstance *ProgramaFidelidade // Instância única do programa.
+var programaOnce sync.Once               // Garante a leitura única do arquivo.
+
+// GetProgramaFidelidade retorna as regras do programa de fidelidade configuradas.
+func GetProgramaFidelidade() *ProgramaFidelidade {
+	programaOnce.Do(func() {
+		programa := &ProgramaFidelidade{PontosPorReal: 1, ValorPonto: entidades.Reais(1), ValidadeDias: 365}
+		if err := lerJSON(CaminhoDados("fidelidade.json"), programa); err != nil {
+			panic(fmt.Sprintf("não foi possível carregar o programa de fidelidade: %v", err))
+		}
+		if programa.PontosPorReal < 0 || programa.ValorPonto.Negativo() || programa.ValidadeDias < 0 {
+			panic("programa de fidelidade inválido: os valores não podem ser negativos")
+		}
+		programaInstance = programa
+	})
+	return programaInstance
+}
+
+// PontosGanhos retorna os pontos ganhos com o valor gasto; frações de ponto são descartadas.
+func (p *ProgramaFidelidade) PontosGanhos(valor entidades.Dinheiro) int64 {
+	if !valor.Positivo() {
+		return 0
+	}
+	return valor.Centavos * p.PontosPorReal / 100
+}
+
+// ValorDosPontos retorna o desconto obtido com o resgate dos pontos.
+func (p *ProgramaFidelidade) ValorDosPontos(pontos int64) entidades.Dinheiro {
+	return p.ValorPonto.Multiplicar(pontos)
+}
+
+// Validade retorna a data de expiração dos pontos ganhos em creditadoEm,
+// ou a data zero se os pontos não expiram.
+func (p *ProgramaFidelidade) Validade(creditadoEm time.Time) time.Time {
+	if p.ValidadeDias == 0 {
+		return time.Time{}
+	}
+	return creditadoEm.AddDate(0, 0, p.ValidadeDias)
+}
@@ -0,0 +1,70 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"os"
+	"path/filepath"
+)
+
+// TipoOperacao identifica a alteração realizada sobre os dados de um DAO.
+type TipoOperacao int
+
+const (
+	OpAdicionar TipoOperacao = iota + 1 // Uma entidade foi adicionada.
+	OpRemover                           // Uma entidade foi removida.
+	OpAtualizar                         // Uma entidade foi substituída por uma nova versão.
+)
+
+// Operacao descreve uma alteração feita no DAO e que deve ser gravada pelo Storage.
+type Operacao[E entidades.Entidade] struct {
+	Tipo     TipoOperacao
+	ID       int64
+	Entidade E `json:",omitempty"`
+}
+
+// Storage define como os dados de um DAO são carregados e gravados.
+// Carregar é chamado uma única vez, na criação do DAO. Gravar é chamado após cada
+// alteração com a operação realizada e o estado completo resultante, de modo que cada
+// backend escolhe se grava apenas a operação ou todo o conjunto de dados.
+type Storage[E entidades.Entidade] interface {
+	Carregar() ([]E, error)
+	Gravar(op Operacao[E], dados []E) error
+}
+
+// DiretorioDados retorna o diretório onde os arquivos de dados são mantidos.
+// Pode ser alterado pela variável de ambiente CLP_DADOS.
+func DiretorioDados() string {
+	if dir := os.Getenv("CLP_DADOS"); dir != "" {
+		return dir
+	}
+	return "dados"
+}
+
+// CaminhoDados retorna o caminho de um arquivo dentro do diretório de dados.
+func CaminhoDados(nome string) string {
+	return filepath.Join(DiretorioDados(), nome)
+}
+
+// aplicarOperacao reproduz op sobre dados, como feito pelo DAO ao executá-la.
+// É usada pelos backends que gravam operações em vez do estado completo.
+func aplicarOperacao[E entidades.Entidade](dados []E, op Operacao[E]) []E {
+	switch op.Tipo {
+	case OpAdicionar:
+		return append(dados, op.Entidade)
+	case OpRemover:
+		filtrados := []E{}
+		for _, e := range dados {
+			if e.GetID() != op.ID {
+				filtrados = append(filtrados, e)
+			}
+		}
+		return filtrados
+	case OpAtualizar:
+		for i, e := range dados {
+			if e.GetID() == op.ID {
+				dados[i] = op.Entidade
+			}
+		}
+	}
+	return dados
+}
@@ -0,0 +1,102 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StorageJSON grava todas as entidades de um DAO em um único arquivo JSON.
+// Cada gravação reescreve o arquivo inteiro de forma atômica.
+type StorageJSON[E entidades.Entidade] struct {
+	caminho string
+}
+
+// NewStorageJSON cria um StorageJSON que usa o arquivo informado.
+func NewStorageJSON[E entidades.Entidade](caminho string) *StorageJSON[E] {
+	return &StorageJSON[E]{caminho: caminho}
+}
+
+// Carregar lê as entidades do arquivo. Um arquivo inexistente equivale a um DAO vazio.
+func (s *StorageJSON[E]) Carregar() ([]E, error) {
+	dados := []E{}
+	if err := lerJSON(s.caminho, &dados); err != nil {
+		return nil, err
+	}
+	return dados, nil
+}
+
+// Gravar reescreve o arquivo com o estado atual do DAO.
+func (s *StorageJSON[E]) Gravar(_ Operacao[E], dados []E) error {
+	return gravarJSON(s.caminho, dados)
+}
+
+// lerJSON decodifica o arquivo em destino, ignorando arquivos inexistentes.
+func lerJSON(caminho string, destino any) error {
+	conteudo, err := os.ReadFile(caminho)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(conteudo, destino); err != nil {
+		return fmt.Errorf("%s: %w", caminho, err)
+	}
+	return nil
+}
+
+// gravarJSON grava valor em caminho de forma atômica: o conteúdo é escrito em um
+// arquivo temporário no mesmo diretório, sincronizado em disco e então renomeado
+// sobre o arquivo final. Assim o arquivo nunca fica parcialmente escrito.
+func gravarJSON(caminho string, valor any) error {
+	conteudo, err := json.MarshalIndent(valor, "", "  ")
+	if err != nil {
+		return err
+	}
+	return gravarAtomico(caminho, conteudo)
+}
+
+// gravarAtomico substitui o conteúdo de caminho usando arquivo temporário e rename.
+func gravarAtomico(caminho string, conteudo []byte) error {
+	dir := filepath.Dir(caminho)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(caminho)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Sem efeito após o rename bem-sucedido.
+
+	if _, err := tmp.Write(conteudo); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), caminho); err != nil {
+		return err
+	}
+	sincronizarDiretorio(dir)
+	return nil
+}
+
+// sincronizarDiretorio garante que o rename foi registrado em disco.
+// Em sistemas que não permitem sincronizar diretórios o erro é ignorado.
+func sincronizarDiretorio(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,249 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CompactacaoPadrao é o número de registros no log após o qual um snapshot é gerado.
+const CompactacaoPadrao = 100
+
+// tamanhoCabecalho é o tamanho do cabeçalho de cada registro: comprimento e checksum.
+const tamanhoCabecalho = 8
+
+// StorageLog é um Storage baseado em log de escrita antecipada (write-ahead log).
+// Cada operação é anexada ao arquivo <base>.log como um registro no formato
+//
+//	[comprimento uint32][crc32 uint32][payload JSON]
+//
+// e sincronizada em disco antes de Gravar retornar. Na carga, o snapshot
+// <base>.snapshot.json é lido e os registros posteriores a ele são reaplicados.
+// Um registro final incompleto ou com checksum inválido (gravação interrompida)
+// é descartado e o log é truncado no último registro íntegro. Um registro com checksum
+// inválido seguido de outros não pode ser uma gravação interrompida: a carga falha com
+// ErrLogCorrompido e o log é mantido intacto, sem descartar as gravações confirmadas.
+// A cada limiteCompactacao registros o estado completo é gravado em um novo
+// snapshot e o log é esvaziado.
+type StorageLog[E entidades.Entidade] struct {
+	caminhoLog        string
+	caminhoSnapshot   string
+	limiteCompactacao int
+
+	arquivo   *os.File
+	sequencia uint64 // Sequência do último registro gravado.
+	registros int    // Registros no log desde o último snapshot.
+}
+
+// registroLog é o conteúdo de cada registro do log.
+type registroLog[E entidades.Entidade] struct {
+	Sequencia uint64
+	Operacao  Operacao[E]
+}
+
+// snapshotLog é o conteúdo do arquivo de snapshot.
+// Sequencia indica o último registro do log já incorporado aos dados.
+type snapshotLog[E entidades.Entidade] struct {
+	Sequencia uint64
+	Dados     []E
+}
+
+// NewStorageLog cria um StorageLog com os arquivos <base>.log e <base>.snapshot.json.
+// Um limiteCompactacao menor ou igual a zero usa CompactacaoPadrao.
+func NewStorageLog[E entidades.Entidade](base string, limiteCompactacao int) *StorageLog[E] {
+	if limiteCompactacao <= 0 {
+		limiteCompactacao = CompactacaoPadrao
+	}
+	return &StorageLog[E]{
+		caminhoLog:        base + ".log",
+		caminhoSnapshot:   base + ".snapshot.json",
+		limiteCompactacao: limiteCompactacao,
+	}
+}
+
+// Carregar lê o snapshot, reaplica o log e deixa o arquivo aberto para novas gravações.
+func (s *StorageLog[E]) Carregar() ([]E, error) {
+	snapshot := snapshotLog[E]{Dados: []E{}}
+	if err := lerJSON(s.caminhoSnapshot, &snapshot); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.caminhoLog), 0o755); err != nil {
+		return nil, err
+	}
+	arquivo, err := os.OpenFile(s.caminhoLog, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	dados := snapshot.Dados
+	s.sequencia = snapshot.Sequencia
+	s.registros = 0
+
+	conteudo, err := io.ReadAll(arquivo)
+	if err != nil {
+		arquivo.Close()
+		return nil, err
+	}
+
+	offset := 0
+	for {
+		payload, proximo, err := lerRegistro(conteudo, offset)
+		if errors.Is(err, errRegistroIncompleto) {
+			break
+		}
+		if err != nil {
+			arquivo.Close()
+			return nil, fmt.Errorf("%s: %w na posição %d: %v", s.caminhoLog, ErrLogCorrompido, offset, err)
+		}
+		var registro registroLog[E]
+		if err := json.Unmarshal(payload, &registro); err != nil {
+			arquivo.Close()
+			return nil, fmt.Errorf("%s: registro corrompido na posição %d: %w", s.caminhoLog, offset, err)
+		}
+		// Registros já incorporados ao snapshot podem restar se a compactação foi
+		// interrompida entre a gravação do snapshot e o truncamento do log.
+		if registro.Sequencia > s.sequencia {
+			dados = aplicarOperacao(dados, registro.Operacao)
+			s.sequencia = registro.Sequencia
+		}
+		s.registros++
+		offset = proximo
+	}
+
+	if offset < len(conteudo) {
+		// Registro final incompleto ou com checksum inválido: descarta a gravação interrompida.
+		if err := arquivo.Truncate(int64(offset)); err != nil {
+			arquivo.Close()
+			return nil, err
+		}
+		if err := arquivo.Sync(); err != nil {
+			arquivo.Close()
+			return nil, err
+		}
+	}
+	if _, err := arquivo.Seek(int64(offset), io.SeekStart); err != nil {
+		arquivo.Close()
+		return nil, err
+	}
+
+	s.arquivo = arquivo
+	return dados, nil
+}
+
+// Gravar anexa a operação ao log e, ao atingir o limite, compacta em um snapshot.
+// A operação está gravada assim que o registro é sincronizado no log; uma falha na
+// compactação não é um erro de gravação, pois o registro seria reaplicado na próxima carga.
+// Nesse caso, o log é mantido e a compactação é tentada novamente na próxima gravação.
+func (s *StorageLog[E]) Gravar(op Operacao[E], dados []E) error {
+	if s.arquivo == nil {
+		return errors.New("storage de log não foi carregado")
+	}
+
+	payload, err := json.Marshal(registroLog[E]{Sequencia: s.sequencia + 1, Operacao: op})
+	if err != nil {
+		return err
+	}
+	if err := s.anexar(payload); err != nil {
+		return err
+	}
+	s.sequencia++
+	s.registros++
+
+	if s.registros >= s.limiteCompactacao {
+		s.Compactar(dados)
+	}
+	return nil
+}
+
+// Compactar grava dados como novo snapshot e esvazia o log.
+func (s *StorageLog[E]) Compactar(dados []E) error {
+	if s.arquivo == nil {
+		return errors.New("storage de log não foi carregado")
+	}
+	if err := gravarJSON(s.caminhoSnapshot, snapshotLog[E]{Sequencia: s.sequencia, Dados: dados}); err != nil {
+		return err
+	}
+	if err := s.arquivo.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := s.arquivo.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	s.registros = 0
+	return s.arquivo.Sync()
+}
+
+// Fechar libera o arquivo de log.
+func (s *StorageLog[E]) Fechar() error {
+	if s.arquivo == nil {
+		return nil
+	}
+	err := s.arquivo.Close()
+	s.arquivo = nil
+	return err
+}
+
+// anexar escreve um registro no fim do log e o sincroniza em disco.
+// Em caso de falha, tenta desfazer a escrita parcial para não deixar lixo no log.
+func (s *StorageLog[E]) anexar(payload []byte) error {
+	inicio, err := s.arquivo.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	registro := make([]byte, tamanhoCabecalho+len(payload))
+	binary.LittleEndian.PutUint32(registro[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(registro[4:8], crc32.ChecksumIEEE(payload))
+	copy(registro[tamanhoCabecalho:], payload)
+
+	if _, err := s.arquivo.Write(registro); err != nil {
+		s.desfazer(inicio)
+		return err
+	}
+	if err := s.arquivo.Sync(); err != nil {
+		s.desfazer(inicio)
+		return err
+	}
+	return nil
+}
+
+// desfazer retorna o log ao tamanho que tinha antes de uma escrita malsucedida.
+func (s *StorageLog[E]) desfazer(tamanho int64) {
+	s.arquivo.Truncate(tamanho)
+	s.arquivo.Seek(tamanho, io.SeekStart)
+}
+
+// errRegistroIncompleto indica um registro final que termina antes do fim declarado.
+var errRegistroIncompleto = errors.New("registro incompleto")
+
+// lerRegistro decodifica o registro que começa em offset.
+// Retorna errRegistroIncompleto se o registro for o último do log e estiver incompleto ou
+// com checksum inválido, o que indica uma gravação interrompida, e um erro de checksum se
+// o registro inválido for seguido de outros.
+func lerRegistro(conteudo []byte, offset int) (payload []byte, proximo int, err error) {
+	if len(conteudo)-offset < tamanhoCabecalho {
+		return nil, offset, errRegistroIncompleto
+	}
+	tamanho := int(binary.LittleEndian.Uint32(conteudo[offset : offset+4]))
+	checksum := binary.LittleEndian.Uint32(conteudo[offset+4 : offset+8])
+
+	inicio := offset + tamanhoCabecalho
+	if tamanho > len(conteudo)-inicio {
+		return nil, offset, errRegistroIncompleto
+	}
+	payload = conteudo[inicio : inicio+tamanho]
+	if crc32.ChecksumIEEE(payload) != checksum {
+		if inicio+tamanho == len(conteudo) {
+			return nil, offset, errRegistroIncompleto
+		}
+		return nil, offset, fmt.Errorf("checksum inválido em registro de %d bytes seguido de outros registros", tamanho)
+	}
+	return payload, inicio + tamanho, nil
+}
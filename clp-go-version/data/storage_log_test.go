@@ -0,0 +1,131 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// gravarProdutos cria um log com um registro de adição para cada nome e retorna o
+// caminho do arquivo de log.
+func gravarProdutos(t *testing.T, nomes ...string) (base, caminhoLog string) {
+	t.Helper()
+	base = filepath.Join(t.TempDir(), "produtos")
+	storage := NewStorageLog[*entidades.Produto](base, 1000)
+	if _, err := storage.Carregar(); err != nil {
+		t.Fatal(err)
+	}
+	for i, nome := range nomes {
+		produto := entidades.NewProduto(nome, entidades.Reais(100))
+		produto.SetID(int64(i + 1))
+		if err := storage.Gravar(Operacao[*entidades.Produto]{Tipo: OpAdicionar, Entidade: produto}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := storage.Fechar(); err != nil {
+		t.Fatal(err)
+	}
+	return base, base + ".log"
+}
+
+func TestStorageLogDescartaRegistroFinalInterrompido(t *testing.T) {
+	base, caminhoLog := gravarProdutos(t, "Arroz", "Feijão")
+	conteudo, err := os.ReadFile(caminhoLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Simula uma gravação interrompida: o último byte do último registro não chegou ao disco.
+	if err := os.WriteFile(caminhoLog, conteudo[:len(conteudo)-1], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := NewStorageLog[*entidades.Produto](base, 1000)
+	dados, err := storage.Carregar()
+	if err != nil {
+		t.Fatalf("Carregar: %v", err)
+	}
+	defer storage.Fechar()
+	if len(dados) != 1 || dados[0].GetNome() != "Arroz" {
+		t.Fatalf("dados = %v, esperado apenas Arroz", dados)
+	}
+}
+
+func TestStorageLogRecusaRegistroCorrompidoNoMeio(t *testing.T) {
+	base, caminhoLog := gravarProdutos(t, "Arroz", "Feijão", "Sal")
+	conteudo, err := os.ReadFile(caminhoLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conteudo[tamanhoCabecalho+2] ^= 0xff // Danifica o payload do primeiro registro.
+	if err := os.WriteFile(caminhoLog, conteudo, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := NewStorageLog[*entidades.Produto](base, 1000)
+	if _, err := storage.Carregar(); !errors.Is(err, ErrLogCorrompido) {
+		t.Fatalf("Carregar: erro = %v, esperado ErrLogCorrompido", err)
+	}
+	depois, err := os.ReadFile(caminhoLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(depois) != len(conteudo) {
+		t.Fatalf("o log foi truncado de %d para %d bytes", len(conteudo), len(depois))
+	}
+}
+
+func TestStorageLogFalhaNaCompactacaoNaoDesfazGravacao(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "produtos")
+	storage := NewStorageLog[*entidades.Produto](base, 2)
+	dao, err := NewDAOPersistente[*entidades.Produto](storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Um diretório no lugar do snapshot faz todas as compactações falharem.
+	if err := os.Mkdir(base+".snapshot.json", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, nome := range []string{"Arroz", "Feijão", "Sal"} {
+		if err := dao.Adicionar(entidades.NewProduto(nome, entidades.Reais(100))); err != nil {
+			t.Fatalf("Adicionar(%s): %v", nome, err)
+		}
+	}
+	alterado, err := dao.Buscar(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alterado = alterado.Clonar()
+	alterado.SetValor(entidades.Reais(250))
+	if err := dao.Atualizar(alterado); err != nil {
+		t.Fatalf("Atualizar: %v", err)
+	}
+	if err := dao.Remover(1); err != nil {
+		t.Fatalf("Remover: %v", err)
+	}
+	if err := storage.Fechar(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(base + ".snapshot.json"); err != nil {
+		t.Fatal(err)
+	}
+	reaberto := NewStorageLog[*entidades.Produto](base, 2)
+	carregados, err := reaberto.Carregar()
+	if err != nil {
+		t.Fatalf("Carregar: %v", err)
+	}
+	defer reaberto.Fechar()
+
+	emMemoria := dao.GetDados()
+	if len(carregados) != len(emMemoria) {
+		t.Fatalf("%d produtos carregados, esperado %d", len(carregados), len(emMemoria))
+	}
+	for i, p := range emMemoria {
+		if *carregados[i] != *p {
+			t.Errorf("produto carregado %+v, esperado %+v", *carregados[i], *p)
+		}
+	}
+}
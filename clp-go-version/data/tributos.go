@@ -0,0 +1,89 @@
+package data
+
+import (
+	"clp-go-version/entidades"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ConfiguracaoTributaria reúne as alíquotas de cada estado e o estado (UF) da loja.
+//
+// É lida do arquivo tributos.json no diretório de dados, no formato
+//
+//	{
+//	  "UF": "SP",
+//	  "Estados": {
+//	    "SP": {
+//	      "Padrao": {"ICMS": 1800, "PIS": 165, "COFINS": 760, "ISS": 500},
+//	      "PorNCM": {"1006": {"ICMS": 700}}
+//	    }
+//	  }
+//	}
+//
+// com as alíquotas em centésimos de ponto (1800 = 18%). Sem o arquivo, é usada a
+// configuração de configuracaoTributariaPadrao.
+type ConfiguracaoTributaria struct {
+	UF      string
+	Estados map[string]entidades.AliquotasEstado
+}
+
+// configuracaoTributariaPadrao são as alíquotas usadas quando não há arquivo de configuração:
+// ICMS de 18%, PIS de 1,65%, COFINS de 7,6% (regime não cumulativo) e ISS de 5%.
+var configuracaoTributariaPadrao = ConfiguracaoTributaria{
+	UF: "SP",
+	Estados: map[string]entidades.AliquotasEstado{
+		"SP": {Padrao: entidades.Aliquotas{entidades.ICMS: 1800, entidades.PIS: 165, entidades.COFINS: 760, entidades.ISS: 500}},
+	},
+}
+
+var (
+	regraTributaria     entidades.RegraTributaria // Regra em uso.
+	regraTributariaMu   sync.Mutex                // Protege regraTributaria.
+	regraTributariaOnce sync.Once                 // Garante a leitura única do arquivo.
+)
+
+// GetRegraTributaria retorna a regra usada para calcular os tributos das vendas finalizadas.
+// Por padrão é uma entidades.RegraPorEstado com as alíquotas do estado da loja.
+func GetRegraTributaria() entidades.RegraTributaria {
+	regraTributariaOnce.Do(func() {
+		var configuracao ConfiguracaoTributaria
+		if err := lerJSON(CaminhoDados("tributos.json"), &configuracao); err != nil {
+			panic(fmt.Sprintf("não foi possível carregar a configuração tributária: %v", err))
+		}
+		if configuracao.Estados == nil {
+			configuracao = configuracaoTributariaPadrao // Arquivo inexistente.
+		}
+		regra, err := configuracao.Regra()
+		if err != nil {
+			panic(fmt.Sprintf("configuração tributária inválida: %v", err))
+		}
+		regraTributariaMu.Lock()
+		if regraTributaria == nil {
+			regraTributaria = regra
+		}
+		regraTributariaMu.Unlock()
+	})
+	regraTributariaMu.Lock()
+	defer regraTributariaMu.Unlock()
+	return regraTributaria
+}
+
+// SetRegraTributaria substitui a regra usada para calcular os tributos.
+func SetRegraTributaria(regra entidades.RegraTributaria) {
+	regraTributariaMu.Lock()
+	defer regraTributariaMu.Unlock()
+	regraTributaria = regra
+}
+
+// Regra retorna a entidades.RegraPorEstado do estado da loja.
+// Retorna erro se não houver alíquotas para esse estado.
+func (c ConfiguracaoTributaria) Regra() (entidades.RegraPorEstado, error) {
+	uf := strings.ToUpper(strings.TrimSpace(c.UF))
+	for estado, aliquotas := range c.Estados {
+		if strings.ToUpper(estado) == uf {
+			return entidades.RegraPorEstado{UF: uf, Aliquotas: aliquotas}, nil
+		}
+	}
+	return entidades.RegraPorEstado{}, fmt.Errorf("não há alíquotas para o estado %q", c.UF)
+}
@@ -0,0 +1,203 @@
+package entidades
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TipoMovimentoCaixa classifica uma movimentação de dinheiro no caixa fora das vendas.
+type TipoMovimentoCaixa string
+
+const (
+	MovimentoSangria    TipoMovimentoCaixa = "sangria"    // Retirada de dinheiro da gaveta.
+	MovimentoSuprimento TipoMovimentoCaixa = "suprimento" // Reforço de dinheiro na gaveta.
+)
+
+// MovimentoCaixa registra uma sangria ou um suprimento de dinheiro no caixa.
+type MovimentoCaixa struct {
+	Tipo     TipoMovimentoCaixa
+	Valor    Dinheiro
+	Motivo   string
+	DataHora time.Time
+}
+
+// Caixa representa uma sessão de caixa, da abertura ao fechamento.
+//
+// O caixa é aberto com um fundo de troco em dinheiro; as vendas finalizadas enquanto ele
+// está aberto e as sangrias e suprimentos são atribuídos a ele. No fechamento, o operador
+// informa os valores contados em cada forma de pagamento sem ver os valores esperados
+// (contagem cega), e ambos ficam registrados para a conferência.
+type Caixa struct {
+	ID         int64
+	Versao     int64
+	Operador   string
+	AbertoEm   time.Time
+	FundoTroco Dinheiro         // Dinheiro na gaveta na abertura.
+	Movimentos []MovimentoCaixa `json:",omitempty"` // Sangrias e suprimentos.
+	FechadoEm  time.Time        // Zero enquanto o caixa está aberto.
+
+	Esperado map[FormaPagamento]Dinheiro `json:",omitempty"` // Valores calculados no fechamento.
+	Contado  map[FormaPagamento]Dinheiro `json:",omitempty"` // Valores informados na contagem.
+}
+
+// NewCaixa cria um caixa aberto agora pelo operador, com o fundo de troco informado.
+func NewCaixa(operador string, fundoTroco Dinheiro) *Caixa {
+	return &Caixa{
+		Operador:   strings.TrimSpace(operador),
+		AbertoEm:   time.Now(),
+		FundoTroco: fundoTroco,
+	}
+}
+
+// GetID retorna o ID do Caixa.
+func (c *Caixa) GetID() int64 {
+	return c.ID
+}
+
+// SetID define o ID do Caixa.
+func (c *Caixa) SetID(id int64) {
+	c.ID = id
+}
+
+// GetVersao retorna a versão do Caixa.
+func (c *Caixa) GetVersao() int64 {
+	return c.Versao
+}
+
+// SetVersao define a versão do Caixa.
+func (c *Caixa) SetVersao(versao int64) {
+	c.Versao = versao
+}
+
+// Aberto informa se o Caixa ainda não foi fechado.
+func (c *Caixa) Aberto() bool {
+	return c.FechadoEm.IsZero()
+}
+
+// Validar verifica se o Caixa tem operador e se os valores não são negativos.
+func (c *Caixa) Validar() error {
+	if c.Operador == "" {
+		return errors.New("informe o operador do caixa")
+	}
+	if c.FundoTroco.Negativo() {
+		return errors.New("o fundo de troco não pode ser negativo")
+	}
+	for _, m := range c.Movimentos {
+		if !m.Valor.Positivo() {
+			return fmt.Errorf("o valor da %s deve ser maior que zero", m.Tipo)
+		}
+	}
+	for forma, valor := range c.Contado {
+		if valor.Negativo() {
+			return fmt.Errorf("o valor contado em %s não pode ser negativo", forma)
+		}
+	}
+	return nil
+}
+
+// Clonar retorna uma cópia do Caixa, incluindo os movimentos e os valores do fechamento.
+func (c *Caixa) Clonar() *Caixa {
+	copia := *c
+	copia.Movimentos = slices.Clone(c.Movimentos)
+	copia.Esperado = maps.Clone(c.Esperado)
+	copia.Contado = maps.Clone(c.Contado)
+	return &copia
+}
+
+// Movimentar registra uma sangria ou um suprimento no Caixa aberto.
+func (c *Caixa) Movimentar(tipo TipoMovimentoCaixa, valor Dinheiro, motivo string) error {
+	if !c.Aberto() {
+		return errors.New("o caixa já foi fechado")
+	}
+	if tipo != MovimentoSangria && tipo != MovimentoSuprimento {
+		return fmt.Errorf("movimento de caixa desconhecido: %q", tipo)
+	}
+	if !valor.Positivo() {
+		return fmt.Errorf("o valor da %s deve ser maior que zero", tipo)
+	}
+	c.Movimentos = append(c.Movimentos, MovimentoCaixa{Tipo: tipo, Valor: valor, Motivo: strings.TrimSpace(motivo), DataHora: time.Now()})
+	return nil
+}
+
+// CalcularEsperado calcula quanto deve haver no Caixa em cada forma de pagamento: em dinheiro,
+// o fundo de troco mais os suprimentos, menos as sangrias e o troco das vendas; em todas as
+// formas, os pagamentos das vendas do caixa que continuam contabilizadas. Vendas de outros
+// caixas são ignoradas.
+func (c *Caixa) CalcularEsperado(vendas []*Venda) map[FormaPagamento]Dinheiro {
+	esperado := map[FormaPagamento]Dinheiro{}
+	for _, forma := range FormasPagamento {
+		esperado[forma] = Reais(0)
+	}
+	dinheiro := c.FundoTroco
+	for _, m := range c.Movimentos {
+		if m.Tipo == MovimentoSangria {
+			dinheiro = dinheiro.Subtrair(m.Valor)
+		} else {
+			dinheiro = dinheiro.Somar(m.Valor)
+		}
+	}
+	esperado[PagamentoDinheiro] = dinheiro
+
+	for _, v := range vendas {
+		if v.CaixaID != c.ID || !v.Contabilizada() {
+			continue
+		}
+		for _, p := range v.Pagamentos {
+			esperado[p.Forma] = esperado[p.Forma].Somar(p.Valor)
+		}
+		esperado[PagamentoDinheiro] = esperado[PagamentoDinheiro].Subtrair(v.Troco())
+	}
+	return esperado
+}
+
+// Fechar encerra o Caixa registrando os valores esperados e os contados.
+// As formas de pagamento não contadas são consideradas zeradas.
+func (c *Caixa) Fechar(esperado, contado map[FormaPagamento]Dinheiro) error {
+	if !c.Aberto() {
+		return errors.New("o caixa já foi fechado")
+	}
+	c.Esperado = maps.Clone(esperado)
+	c.Contado = maps.Clone(contado)
+	c.FechadoEm = time.Now()
+	return nil
+}
+
+// Diferenca retorna o valor contado menos o esperado na forma de pagamento informada:
+// positivo quando sobra e negativo quando falta.
+func (c *Caixa) Diferenca(forma FormaPagamento) Dinheiro {
+	return c.Contado[forma].Subtrair(c.Esperado[forma])
+}
+
+// Conferencia retorna o relatório do fechamento, com os valores esperados, contados e a
+// diferença de cada forma de pagamento.
+func (c *Caixa) Conferencia() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-10s %14s %14s %14s\n", "FORMA", "ESPERADO", "CONTADO", "DIFERENÇA"))
+	total := Reais(0)
+	for _, forma := range FormasPagamento {
+		diferenca := c.Diferenca(forma)
+		total = total.Somar(diferenca)
+		sb.WriteString(fmt.Sprintf("%-10s %14s %14s %14s\n", forma, c.Esperado[forma], c.Contado[forma], diferenca))
+	}
+	sb.WriteString(fmt.Sprintf("%-10s %14s %14s %14s\n", "TOTAL", "", "", total))
+	return sb.String()
+}
+
+// String retorna uma representação textual do Caixa.
+func (c *Caixa) String() string {
+	situacao := "aberto"
+	if !c.Aberto() {
+		situacao = "fechado em " + c.FechadoEm.Format("2006-01-02 15:04:05")
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Caixa[ID=%d, Operador=%s, AbertoEm=%s, FundoTroco=%s, %s]\n",
+		c.ID, c.Operador, c.AbertoEm.Format("2006-01-02 15:04:05"), c.FundoTroco, situacao))
+	for _, m := range c.Movimentos {
+		sb.WriteString(fmt.Sprintf("  %s %-10s %12s  %s\n", m.DataHora.Format("15:04:05"), m.Tipo, m.Valor, m.Motivo))
+	}
+	return sb.String()
+}
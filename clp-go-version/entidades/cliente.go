@@ -0,0 +1,177 @@
+package entidades
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cliente representa um comprador identificado pelo CPF (pessoa física) ou pelo CNPJ
+// (pessoa jurídica). O documento é guardado apenas com os dígitos.
+type Cliente struct {
+	ID           int64
+	Versao       int64
+	Nome         string
+	Documento    string // CPF (11 dígitos) ou CNPJ (14 dígitos), sem pontuação.
+	Email        string `json:",omitempty"`
+	Telefone     string `json:",omitempty"`
+	DataCadastro time.Time
+}
+
+// NewCliente cria um novo Cliente com a data de cadastro atual.
+// O ID é atribuído pelo DAO quando o cliente é adicionado.
+func NewCliente(nome, documento string) *Cliente {
+	return &Cliente{
+		Nome:         strings.TrimSpace(nome),
+		Documento:    NormalizarDocumento(documento),
+		DataCadastro: time.Now(),
+	}
+}
+
+// GetID retorna o ID do Cliente.
+func (c *Cliente) GetID() int64 {
+	return c.ID
+}
+
+// SetID define o ID do Cliente.
+func (c *Cliente) SetID(id int64) {
+	c.ID = id
+}
+
+// GetVersao retorna a versão do Cliente.
+func (c *Cliente) GetVersao() int64 {
+	return c.Versao
+}
+
+// SetVersao define a versão do Cliente.
+func (c *Cliente) SetVersao(versao int64) {
+	c.Versao = versao
+}
+
+// GetNome retorna o nome do Cliente.
+func (c *Cliente) GetNome() string {
+	return c.Nome
+}
+
+// SetNome define o nome do Cliente.
+func (c *Cliente) SetNome(nome string) {
+	c.Nome = strings.TrimSpace(nome)
+}
+
+// GetDocumento retorna o CPF ou CNPJ do Cliente, apenas com os dígitos.
+func (c *Cliente) GetDocumento() string {
+	return c.Documento
+}
+
+// SetDocumento define o CPF ou CNPJ do Cliente, descartando a pontuação.
+func (c *Cliente) SetDocumento(documento string) {
+	c.Documento = NormalizarDocumento(documento)
+}
+
+// PessoaJuridica informa se o Cliente é identificado por CNPJ.
+func (c *Cliente) PessoaJuridica() bool {
+	return len(c.Documento) == 14
+}
+
+// Validar verifica se o Cliente tem nome e um CPF ou CNPJ com dígitos verificadores corretos.
+func (c *Cliente) Validar() error {
+	if c.Nome == "" {
+		return errors.New("o nome do cliente é obrigatório")
+	}
+	switch len(c.Documento) {
+	case 11:
+		if !ValidarCPF(c.Documento) {
+			return fmt.Errorf("CPF inválido: %s", FormatarDocumento(c.Documento))
+		}
+	case 14:
+		if !ValidarCNPJ(c.Documento) {
+			return fmt.Errorf("CNPJ inválido: %s", FormatarDocumento(c.Documento))
+		}
+	default:
+		return fmt.Errorf("documento inválido: %q (informe um CPF ou um CNPJ)", c.Documento)
+	}
+	return nil
+}
+
+// Clonar retorna uma cópia do Cliente.
+func (c *Cliente) Clonar() *Cliente {
+	copia := *c
+	return &copia
+}
+
+// String retorna uma representação textual do Cliente.
+func (c *Cliente) String() string {
+	contato := ""
+	if c.Email != "" {
+		contato += ", Email=" + c.Email
+	}
+	if c.Telefone != "" {
+		contato += ", Telefone=" + c.Telefone
+	}
+	return fmt.Sprintf("Cliente[ID=%d, Nome=%s, Documento=%s%s]", c.ID, c.Nome, FormatarDocumento(c.Documento), contato)
+}
+
+// NormalizarDocumento remove de um CPF ou CNPJ tudo o que não for dígito.
+func NormalizarDocumento(documento string) string {
+	var sb strings.Builder
+	for _, r := range documento {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// FormatarDocumento formata um CPF como 000.000.000-00 e um CNPJ como 00.000.000/0000-00.
+// Outros valores são retornados sem alteração.
+func FormatarDocumento(documento string) string {
+	d := NormalizarDocumento(documento)
+	switch len(d) {
+	case 11:
+		return d[0:3] + "." + d[3:6] + "." + d[6:9] + "-" + d[9:]
+	case 14:
+		return d[0:2] + "." + d[2:5] + "." + d[5:8] + "/" + d[8:12] + "-" + d[12:]
+	}
+	return documento
+}
+
+// ValidarCPF verifica os dois dígitos verificadores de um CPF de 11 dígitos.
+// CPFs com todos os dígitos iguais, como 111.111.111-11, são rejeitados.
+func ValidarCPF(cpf string) bool {
+	d := NormalizarDocumento(cpf)
+	if len(d) != 11 || todosIguais(d) {
+		return false
+	}
+	return digitoVerificador(d[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == d[9] &&
+		digitoVerificador(d[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == d[10]
+}
+
+// ValidarCNPJ verifica os dois dígitos verificadores de um CNPJ de 14 dígitos.
+// CNPJs com todos os dígitos iguais são rejeitados.
+func ValidarCNPJ(cnpj string) bool {
+	d := NormalizarDocumento(cnpj)
+	if len(d) != 14 || todosIguais(d) {
+		return false
+	}
+	return digitoVerificador(d[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == d[12] &&
+		digitoVerificador(d[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == d[13]
+}
+
+// digitoVerificador calcula o dígito verificador de módulo 11 dos dígitos com os pesos informados.
+func digitoVerificador(digitos string, pesos []int) byte {
+	soma := 0
+	for i, peso := range pesos {
+		soma += int(digitos[i]-'0') * peso
+	}
+	resto := soma % 11
+	if resto < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - resto)
+}
+
+// todosIguais informa se todos os caracteres de s são iguais.
+func todosIguais(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
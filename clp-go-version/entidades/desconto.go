@@ -0,0 +1,82 @@
+package entidades
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Desconto representa um desconto percentual ou de valor fixo, concedido em um item
+// ou no total de uma venda. Apenas um dos campos é preenchido; o valor zero de Desconto
+// significa "sem desconto".
+type Desconto struct {
+	Percentual Percentual `json:",omitempty"`
+	Valor      Dinheiro   // Valor fixo, usado quando Percentual é zero.
+}
+
+// DescontoPercentual cria um desconto percentual.
+func DescontoPercentual(p Percentual) Desconto {
+	return Desconto{Percentual: p}
+}
+
+// DescontoFixo cria um desconto de valor fixo.
+func DescontoFixo(valor Dinheiro) Desconto {
+	return Desconto{Valor: valor}
+}
+
+// ParseDesconto interpreta um desconto digitado pelo usuário: valores terminados em "%"
+// são percentuais ("10%", "7,5%") e os demais são valores fixos ("5,00", "R$ 5").
+func ParseDesconto(texto string) (Desconto, error) {
+	s := strings.TrimSpace(texto)
+	if strings.HasSuffix(s, "%") {
+		p, err := ParsePercentual(s)
+		if err != nil {
+			return Desconto{}, err
+		}
+		return DescontoPercentual(p), nil
+	}
+	valor, err := ParseDinheiro(s, MoedaPadrao)
+	if err != nil {
+		return Desconto{}, err
+	}
+	return DescontoFixo(valor), nil
+}
+
+// Vazio informa se não há desconto.
+func (d Desconto) Vazio() bool {
+	return d.Percentual == 0 && d.Valor.Zerado()
+}
+
+// Calcular retorna o valor do desconto sobre a base informada.
+// O desconto percentual é arredondado para o centavo mais próximo (meio centavo para cima),
+// e o desconto nunca passa da base.
+func (d Desconto) Calcular(base Dinheiro) Dinheiro {
+	valor := d.Valor
+	if d.Percentual != 0 {
+		valor = d.Percentual.Aplicar(base, ArredondamentoMeioParaCima)
+	}
+	if valor.Comparar(base) > 0 {
+		return base
+	}
+	return valor
+}
+
+// Validar verifica se o desconto é aplicável à base: não pode ser negativo nem maior que ela.
+func (d Desconto) Validar(base Dinheiro) error {
+	switch {
+	case d.Percentual < 0 || d.Valor.Negativo():
+		return fmt.Errorf("desconto negativo: %s", d)
+	case d.Percentual > CemPorCento:
+		return fmt.Errorf("desconto maior que 100%%: %s", d)
+	case d.Valor.Comparar(base) > 0:
+		return fmt.Errorf("desconto de %s maior que o valor de %s", d, base)
+	}
+	return nil
+}
+
+// String retorna o desconto como "10,00%" ou "R$ 5,00".
+func (d Desconto) String() string {
+	if d.Percentual != 0 {
+		return d.Percentual.String()
+	}
+	return d.Valor.String()
+}
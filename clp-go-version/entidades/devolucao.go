@@ -0,0 +1,178 @@
+package entidades
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ItemDevolucao registra a quantidade devolvida de um item de uma venda.
+// O item é identificado pela sua posição na venda, pois a mesma venda pode ter
+// mais de um item do mesmo produto, com valores diferentes.
+type ItemDevolucao struct {
+	Posicao     int // Posição do ItemVenda na venda, a partir de zero.
+	ProdutoID   int64
+	NomeProduto string
+	Quantidade  int
+	Valor       Dinheiro // Valor unitário cobrado na venda.
+	Desconto    Dinheiro // Parte dos descontos da venda correspondente à quantidade devolvida.
+}
+
+// Total retorna o valor a creditar pelo item: o valor unitário multiplicado pela quantidade,
+// menos a parte proporcional dos descontos concedidos na venda.
+func (i ItemDevolucao) Total() Dinheiro {
+	return i.Valor.Multiplicar(int64(i.Quantidade)).Subtrair(i.Desconto)
+}
+
+// String retorna a linha do item formatada para a nota de crédito.
+func (i ItemDevolucao) String() string {
+	return fmt.Sprintf("%15s %12s x %5d = %12s", i.NomeProduto, i.Valor, i.Quantidade, i.Total())
+}
+
+// Devolucao representa a devolução de parte dos itens de uma venda finalizada.
+// A venda original não é alterada: as devoluções são registradas à parte e somadas
+// para saber quanto de cada item ainda pode ser devolvido.
+type Devolucao struct {
+	ID       int64
+	Versao   int64
+	VendaID  int64
+	DataHora time.Time
+	Motivo   string
+	Itens    []ItemDevolucao
+}
+
+// NewDevolucao cria uma devolução vazia da venda informada.
+// O ID é atribuído pelo DAO quando a devolução é adicionada.
+func NewDevolucao(venda *Venda, motivo string) *Devolucao {
+	return &Devolucao{
+		VendaID:  venda.GetID(),
+		DataHora: time.Now(),
+		Motivo:   strings.TrimSpace(motivo),
+		Itens:    []ItemDevolucao{},
+	}
+}
+
+// GetID retorna o ID da Devolucao.
+func (d *Devolucao) GetID() int64 {
+	return d.ID
+}
+
+// SetID define o ID da Devolucao.
+func (d *Devolucao) SetID(id int64) {
+	d.ID = id
+}
+
+// GetVersao retorna a versão da Devolucao.
+func (d *Devolucao) GetVersao() int64 {
+	return d.Versao
+}
+
+// SetVersao define a versão da Devolucao.
+func (d *Devolucao) SetVersao(versao int64) {
+	d.Versao = versao
+}
+
+// GetItens retorna a lista de itens devolvidos.
+func (d *Devolucao) GetItens() []ItemDevolucao {
+	return d.Itens
+}
+
+// AdicionarItem inclui na devolução uma quantidade do item da venda na posição informada.
+// Retorna erro se a posição não existir na venda.
+func (d *Devolucao) AdicionarItem(venda *Venda, posicao, quantidade int) error {
+	if posicao < 0 || posicao >= len(venda.Itens) {
+		return fmt.Errorf("a venda %d não tem o item %d", venda.GetID(), posicao+1)
+	}
+	item := venda.Itens[posicao]
+	bruto := item.Valor.Multiplicar(int64(quantidade))
+	liquido := venda.LiquidoDoItem(posicao).MultiplicarFracao(int64(quantidade), int64(item.Quantidade), ArredondamentoMeioParaCima)
+	d.Itens = append(d.Itens, ItemDevolucao{
+		Posicao:     posicao,
+		ProdutoID:   item.Produto.GetID(),
+		NomeProduto: item.Produto.GetNome(),
+		Quantidade:  quantidade,
+		Valor:       item.Valor,
+		Desconto:    bruto.Subtrair(liquido),
+	})
+	return nil
+}
+
+// Validar verifica se a Devolucao tem motivo, ao menos um item e quantidades positivas.
+// A verificação contra as quantidades vendidas é feita por VerificarDevolucao.
+func (d *Devolucao) Validar() error {
+	if d.Motivo == "" {
+		return errors.New("informe o motivo da devolução")
+	}
+	if len(d.Itens) == 0 {
+		return errors.New("a devolução precisa ter ao menos um item")
+	}
+	for _, item := range d.Itens {
+		if item.Quantidade <= 0 {
+			return fmt.Errorf("quantidade inválida para %s: %d", item.NomeProduto, item.Quantidade)
+		}
+	}
+	return nil
+}
+
+// Total calcula o valor a creditar ao cliente pela Devolucao.
+func (d *Devolucao) Total() Dinheiro {
+	total := Reais(0)
+	for _, item := range d.Itens {
+		total = total.Somar(item.Total())
+	}
+	return total
+}
+
+// String retorna a nota de crédito da Devolucao.
+func (d *Devolucao) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Devolucao[ID=%d, Venda=%d, DataHora=%s]\n", d.ID, d.VendaID, d.DataHora.Format("2006-01-02 15:04:05")))
+	sb.WriteString(fmt.Sprintf("Motivo: %s\n", d.Motivo))
+	sb.WriteString("Itens:\n")
+	for _, item := range d.Itens {
+		sb.WriteString(fmt.Sprintf("  %s\n", item.String()))
+	}
+	sb.WriteString(fmt.Sprintf("CRÉDITO: %s\n", d.Total()))
+	return sb.String()
+}
+
+// QuantidadesDevolviveis retorna, para cada item da venda, a quantidade que ainda pode
+// ser devolvida, descontadas as devoluções já registradas.
+func QuantidadesDevolviveis(venda *Venda, devolucoes []*Devolucao) []int {
+	restantes := make([]int, len(venda.Itens))
+	for i, item := range venda.Itens {
+		restantes[i] = item.Quantidade
+	}
+	for _, dev := range devolucoes {
+		for _, item := range dev.Itens {
+			if item.Posicao >= 0 && item.Posicao < len(restantes) {
+				restantes[item.Posicao] -= item.Quantidade
+			}
+		}
+	}
+	return restantes
+}
+
+// VerificarDevolucao confere se a nova devolução pertence à venda, se a venda está
+// finalizada e se nenhum item é devolvido em quantidade maior que a vendida, considerando
+// as devoluções anteriores.
+func VerificarDevolucao(venda *Venda, anteriores []*Devolucao, nova *Devolucao) error {
+	if nova.VendaID != venda.GetID() {
+		return fmt.Errorf("a devolução não pertence à venda %d", venda.GetID())
+	}
+	if venda.GetStatus() != VendaFinalizada {
+		return fmt.Errorf("somente vendas finalizadas aceitam devolução; a venda %d está %s", venda.GetID(), venda.GetStatus())
+	}
+	restantes := QuantidadesDevolviveis(venda, anteriores)
+	for _, item := range nova.Itens {
+		if item.Posicao < 0 || item.Posicao >= len(restantes) || venda.Itens[item.Posicao].Produto.GetID() != item.ProdutoID {
+			return fmt.Errorf("o item %d não corresponde à venda %d", item.Posicao+1, venda.GetID())
+		}
+		restantes[item.Posicao] -= item.Quantidade
+		if restantes[item.Posicao] < 0 {
+			return fmt.Errorf("quantidade devolvida de %s maior que a vendida", item.NomeProduto)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package entidades
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MoedaPadrao é o código ISO 4217 da moeda usada pelo sistema.
+const MoedaPadrao = "BRL"
+
+// ErrValorInvalido indica um texto que não pôde ser interpretado como valor monetário.
+var ErrValorInvalido = errors.New("valor monetário inválido")
+
+// ModoArredondamento define como frações de centavo são tratadas.
+type ModoArredondamento int
+
+const (
+	ArredondamentoMeioParaCima ModoArredondamento = iota // Meio centavo afasta de zero (arredondamento comercial).
+	ArredondamentoMeioParaPar                            // Meio centavo vai para o centavo par (arredondamento bancário).
+	ArredondamentoParaBaixo                              // Descarta a fração, em direção a zero.
+	ArredondamentoParaCima                               // Qualquer fração afasta de zero.
+)
+
+// Dinheiro representa um valor monetário em ponto fixo.
+// O valor é mantido como um inteiro de centavos, o que evita os erros de arredondamento
+// acumulados por float64 em somas de muitos valores.
+type Dinheiro struct {
+	Centavos int64  // Valor em centavos.
+	Moeda    string // Código ISO 4217 da moeda, como "BRL".
+}
+
+// NewDinheiro cria um Dinheiro a partir de centavos e do código da moeda.
+func NewDinheiro(centavos int64, moeda string) Dinheiro {
+	return Dinheiro{Centavos: centavos, Moeda: moeda}
+}
+
+// Reais cria um Dinheiro na moeda padrão a partir de centavos.
+func Reais(centavos int64) Dinheiro {
+	return NewDinheiro(centavos, MoedaPadrao)
+}
+
+// ParseDinheiro interpreta um valor digitado pelo usuário.
+// Aceita vírgula ou ponto como separador decimal ("10,50" e "10.50"), separadores de
+// milhar ("1.234,56" e "1,234.56") e o símbolo da moeda ("R$ 10,50").
+// Valores com mais de duas casas decimais são rejeitados.
+func ParseDinheiro(texto string, moeda string) (Dinheiro, error) {
+	return parseDinheiro(texto, moeda, separarDecimal)
+}
+
+// ParseDinheiroDecimal interpreta um valor como ParseDinheiro, mas com o separador decimal
+// informado (',' ou '.') em vez de deduzi-lo do texto. O outro separador só é aceito como
+// separador de milhar. Assim, com vírgula decimal, "1.234" vale 1234,00 e "1,234" é
+// rejeitado por ter três casas decimais; com ponto decimal, ocorre o contrário.
+func ParseDinheiroDecimal(texto string, moeda string, decimal rune) (Dinheiro, error) {
+	if decimal != ',' && decimal != '.' {
+		return Dinheiro{}, fmt.Errorf("%w: separador decimal %q", ErrValorInvalido, decimal)
+	}
+	return parseDinheiro(texto, moeda, func(s string) (string, string, bool) {
+		return separarDecimalFixo(s, string(decimal))
+	})
+}
+
+// parseDinheiro interpreta o valor usando separar para obter a parte inteira e a fração.
+func parseDinheiro(texto string, moeda string, separar func(s string) (inteiro, fracao string, ok bool)) (Dinheiro, error) {
+	s := strings.TrimSpace(texto)
+	s = strings.TrimPrefix(s, simboloMoeda(moeda))
+	s = strings.TrimPrefix(s, moeda)
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+
+	negativo := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	inteiro, fracao, ok := separar(s)
+	if !ok || inteiro == "" && fracao == "" {
+		return Dinheiro{}, fmt.Errorf("%w: %q", ErrValorInvalido, texto)
+	}
+	if inteiro == "" {
+		inteiro = "0"
+	}
+	for len(fracao) < 2 {
+		fracao += "0"
+	}
+
+	centavos, err := strconv.ParseInt(inteiro+fracao, 10, 64)
+	if err != nil {
+		return Dinheiro{}, fmt.Errorf("%w: %q", ErrValorInvalido, texto)
+	}
+	if negativo {
+		centavos = -centavos
+	}
+	return NewDinheiro(centavos, moeda), nil
+}
+
+// separarDecimal separa a parte inteira (sem separadores de milhar) da fração.
+// Quando há os dois separadores, o último é o decimal. Quando há apenas um, ele é
+// decimal se aparece uma única vez seguido de uma ou duas casas, e de milhar caso contrário.
+func separarDecimal(s string) (inteiro, fracao string, ok bool) {
+	ultimo := strings.LastIndexAny(s, ",.")
+	if ultimo < 0 {
+		return s, "", apenasDigitos(s)
+	}
+
+	separador, outro := s[ultimo:ultimo+1], ","
+	if separador == "," {
+		outro = "."
+	}
+	decimal := strings.Contains(s[:ultimo], outro) ||
+		strings.Count(s, separador) == 1 && len(s)-ultimo-1 <= 2
+
+	if !decimal {
+		inteiro, ok = removerMilhar(s)
+		return inteiro, "", ok
+	}
+
+	inteiro, ok = removerMilhar(s[:ultimo])
+	fracao = s[ultimo+1:]
+	return inteiro, fracao, ok && len(fracao) <= 2 && apenasDigitos(fracao)
+}
+
+// separarDecimalFixo separa a parte inteira (sem separadores de milhar) da fração, com
+// decimal como separador decimal, que pode aparecer no máximo uma vez.
+func separarDecimalFixo(s, decimal string) (inteiro, fracao string, ok bool) {
+	if strings.Count(s, decimal) > 1 {
+		return "", "", false
+	}
+	inteiro, fracao, _ = strings.Cut(s, decimal)
+	inteiro, ok = removerMilhar(inteiro)
+	return inteiro, fracao, ok && len(fracao) <= 2 && apenasDigitos(fracao)
+}
+
+// removerMilhar remove os separadores de milhar de s, exigindo grupos de três dígitos.
+func removerMilhar(s string) (string, bool) {
+	grupos := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '.' })
+	if len(grupos) == 0 || len(strings.Join(grupos, "")) != len(s)-len(grupos)+1 {
+		return "", s == ""
+	}
+	for i, g := range grupos {
+		if !apenasDigitos(g) || i > 0 && len(g) != 3 {
+			return "", false
+		}
+	}
+	return strings.Join(grupos, ""), true
+}
+
+// apenasDigitos informa se s contém somente dígitos decimais.
+func apenasDigitos(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Somar retorna a soma de d e outro.
+func (d Dinheiro) Somar(outro Dinheiro) Dinheiro {
+	return NewDinheiro(d.Centavos+outro.Centavos, moedaComum(d, outro))
+}
+
+// Subtrair retorna d menos outro.
+func (d Dinheiro) Subtrair(outro Dinheiro) Dinheiro {
+	return NewDinheiro(d.Centavos-outro.Centavos, moedaComum(d, outro))
+}
+
+// Multiplicar retorna d multiplicado por uma quantidade inteira.
+func (d Dinheiro) Multiplicar(quantidade int64) Dinheiro {
+	return NewDinheiro(d.Centavos*quantidade, d.Moeda)
+}
+
+// MultiplicarFracao retorna d multiplicado por numerador/denominador,
+// arredondando o resultado para centavos conforme o modo informado.
+// Por exemplo, 12,5% de d é d.MultiplicarFracao(125, 1000, modo).
+func (d Dinheiro) MultiplicarFracao(numerador, denominador int64, modo ModoArredondamento) Dinheiro {
+	return NewDinheiro(dividir(d.Centavos*numerador, denominador, modo), d.Moeda)
+}
+
+// Comparar retorna -1, 0 ou 1 conforme d seja menor, igual ou maior que outro.
+func (d Dinheiro) Comparar(outro Dinheiro) int {
+	moedaComum(d, outro)
+	switch {
+	case d.Centavos < outro.Centavos:
+		return -1
+	case d.Centavos > outro.Centavos:
+		return 1
+	}
+	return 0
+}
+
+// Positivo informa se o valor é maior que zero.
+func (d Dinheiro) Positivo() bool {
+	return d.Centavos > 0
+}
+
+// Negativo informa se o valor é menor que zero.
+func (d Dinheiro) Negativo() bool {
+	return d.Centavos < 0
+}
+
+// Zerado informa se o valor é zero.
+func (d Dinheiro) Zerado() bool {
+	return d.Centavos == 0
+}
+
+// Decimal retorna o valor com ponto decimal e sem símbolo, como "1234.56".
+func (d Dinheiro) Decimal() string {
+	sinal, centavos := "", d.Centavos
+	if centavos < 0 {
+		sinal, centavos = "-", -centavos
+	}
+	return fmt.Sprintf("%s%d.%02d", sinal, centavos/100, centavos%100)
+}
+
+// String retorna o valor formatado no padrão brasileiro, como "R$ 1.234,56".
+func (d Dinheiro) String() string {
+	sinal, centavos := "", d.Centavos
+	if centavos < 0 {
+		sinal, centavos = "-", -centavos
+	}
+
+	inteiro := strconv.FormatInt(centavos/100, 10)
+	var sb strings.Builder
+	for i, r := range inteiro {
+		if i > 0 && (len(inteiro)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(r)
+	}
+	return fmt.Sprintf("%s%s %s,%02d", sinal, simboloMoeda(d.Moeda), sb.String(), centavos%100)
+}
+
+// UnmarshalJSON aceita tanto o formato atual ({"Centavos":1050,"Moeda":"BRL"}) quanto
+// um número decimal em reais, formato usado pelos arquivos gravados antes do Dinheiro.
+func (d *Dinheiro) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] != '{' && !bytes.Equal(b, []byte("null")) {
+		var reais float64
+		if err := json.Unmarshal(b, &reais); err != nil {
+			return err
+		}
+		*d = Reais(int64(math.Round(reais * 100)))
+		return nil
+	}
+
+	type dinheiro Dinheiro // Evita a recursão em UnmarshalJSON.
+	return json.Unmarshal(b, (*dinheiro)(d))
+}
+
+// simboloMoeda retorna o símbolo usado na formatação de cada moeda.
+func simboloMoeda(moeda string) string {
+	switch moeda {
+	case "BRL", "":
+		return "R$"
+	case "USD":
+		return "US$"
+	case "EUR":
+		return "€"
+	}
+	return moeda
+}
+
+// moedaComum retorna a moeda de uma operação entre dois valores.
+// O valor zero de Dinheiro (sem moeda) é compatível com qualquer moeda.
+// Operar valores de moedas diferentes é um erro de programação e causa pânico.
+func moedaComum(a, b Dinheiro) string {
+	switch {
+	case a.Moeda == "":
+		return b.Moeda
+	case b.Moeda == "" || a.Moeda == b.Moeda:
+		return a.Moeda
+	}
+	panic(fmt.Sprintf("operação entre moedas diferentes: %s e %s", a.Moeda, b.Moeda))
+}
+
+// dividir calcula n/d arredondando conforme modo.
+func dividir(n, d int64, modo ModoArredondamento) int64 {
+	if d < 0 {
+		n, d = -n, -d
+	}
+	q, r := n/d, n%d
+	if r == 0 {
+		return q
+	}
+
+	sinal := int64(1)
+	if n < 0 {
+		sinal, r = -1, -r
+	}
+
+	switch modo {
+	case ArredondamentoParaBaixo:
+		return q
+	case ArredondamentoParaCima:
+		return q + sinal
+	case ArredondamentoMeioParaPar:
+		if 2*r > d || 2*r == d && q%2 != 0 {
+			return q + sinal
+		}
+		return q
+	}
+	if 2*r >= d {
+		return q + sinal
+	}
+	return q
+}
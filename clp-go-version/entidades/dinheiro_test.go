@@ -0,0 +1,39 @@
+package entidades
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDinheiroDecimal(t *testing.T) {
+	casos := []struct {
+		texto    string
+		decimal  rune
+		centavos int64
+		valido   bool
+	}{
+		{"1.234", ',', 123400, true},
+		{"1,234", ',', 0, false}, // Três casas decimais.
+		{"1.234,56", ',', 123456, true},
+		{"R$ 9,9", ',', 990, true},
+		{"12.3", ',', 0, false}, // Ponto fora de um grupo de milhar.
+		{"1,2,3", ',', 0, false},
+		{"1,234", '.', 123400, true},
+		{"1.234", '.', 0, false},
+		{"1,234.56", '.', 123456, true},
+		{"-9.90", '.', -990, true},
+		{"9,90", '.', 0, false},
+		{"9", '.', 900, true},
+	}
+	for _, c := range casos {
+		valor, err := ParseDinheiroDecimal(c.texto, MoedaPadrao, c.decimal)
+		switch {
+		case c.valido && err != nil:
+			t.Errorf("ParseDinheiroDecimal(%q, %q): %v", c.texto, c.decimal, err)
+		case c.valido && valor.Centavos != c.centavos:
+			t.Errorf("ParseDinheiroDecimal(%q, %q) = %d centavos, esperado %d", c.texto, c.decimal, valor.Centavos, c.centavos)
+		case !c.valido && !errors.Is(err, ErrValorInvalido):
+			t.Errorf("ParseDinheiroDecimal(%q, %q): erro = %v, esperado ErrValorInvalido", c.texto, c.decimal, err)
+		}
+	}
+}
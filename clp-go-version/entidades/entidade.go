@@ -13,8 +13,27 @@ type Entidade interface {
 	// Aqui, GetID deve ser implementado por qualquer tipo que satisfaça a interface Entidade.
 	GetID() int64
 
+	// SetID define o identificador da entidade.
+	// É usado pelo DAO, que atribui o ID no momento em que a entidade é adicionada.
+	SetID(id int64)
+
+	// GetVersao retorna a versão da entidade, incrementada pelo DAO a cada atualização.
+	// A versão permite detectar quando uma entidade foi alterada por outra operação
+	// entre a leitura e a gravação (controle de concorrência otimista).
+	GetVersao() int64
+
+	// SetVersao define a versão da entidade. É usado pelo DAO.
+	SetVersao(versao int64)
+
 	// String retorna uma representação textual da entidade.
 	// Este método é semelhante ao método `toString()` em outras linguagens, mas em Go ele faz parte
 	// da interface `fmt.Stringer` se implementado.
 	String() string
 }
+
+// Validavel é implementada pelas entidades que sabem verificar a consistência dos próprios dados.
+// O DAO chama Validar antes de adicionar ou atualizar uma entidade e recusa a operação
+// se um erro for retornado.
+type Validavel interface {
+	Validar() error
+}
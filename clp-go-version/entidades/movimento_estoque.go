@@ -0,0 +1,70 @@
+package entidades
+
+import (
+	"fmt"
+	"time"
+)
+
+// TipoMovimento classifica um movimento de estoque.
+type TipoMovimento string
+
+const (
+	MovimentoEntrada TipoMovimento = "entrada" // Mercadoria recebida ou devolvida ao estoque.
+	MovimentoSaida   TipoMovimento = "saida"   // Mercadoria vendida ou baixada do estoque.
+	MovimentoAjuste  TipoMovimento = "ajuste"  // Correção do saldo após contagem.
+)
+
+// MovimentoEstoque registra uma alteração no estoque de um produto.
+// Os movimentos formam o histórico (livro-razão) do estoque: o saldo de um produto
+// é a soma das quantidades de todos os seus movimentos.
+type MovimentoEstoque struct {
+	ID          int64
+	Versao      int64
+	ProdutoID   int64
+	NomeProduto string
+	Tipo        TipoMovimento
+	Quantidade  int   // Variação do estoque: positiva para entradas, negativa para saídas.
+	Saldo       int   // Saldo do produto após o movimento.
+	VendaID     int64 `json:",omitempty"` // Venda que originou o movimento, se houver.
+	Motivo      string
+	DataHora    time.Time
+}
+
+// NewMovimentoEstoque cria um movimento de estoque com a data e hora atuais.
+func NewMovimentoEstoque(produto *Produto, tipo TipoMovimento, quantidade int, motivo string) *MovimentoEstoque {
+	return &MovimentoEstoque{
+		ProdutoID:   produto.GetID(),
+		NomeProduto: produto.GetNome(),
+		Tipo:        tipo,
+		Quantidade:  quantidade,
+		Saldo:       produto.GetEstoque(),
+		Motivo:      motivo,
+		DataHora:    time.Now(),
+	}
+}
+
+// GetID retorna o ID do MovimentoEstoque.
+func (m *MovimentoEstoque) GetID() int64 {
+	return m.ID
+}
+
+// SetID define o ID do MovimentoEstoque.
+func (m *MovimentoEstoque) SetID(id int64) {
+	m.ID = id
+}
+
+// GetVersao retorna a versão do MovimentoEstoque.
+func (m *MovimentoEstoque) GetVersao() int64 {
+	return m.Versao
+}
+
+// SetVersao define a versão do MovimentoEstoque.
+func (m *MovimentoEstoque) SetVersao(versao int64) {
+	m.Versao = versao
+}
+
+// String retorna uma representação textual do MovimentoEstoque.
+func (m *MovimentoEstoque) String() string {
+	return fmt.Sprintf("%s %-8s %15s %+6d saldo %6d  %s",
+		m.DataHora.Format("2006-01-02 15:04:05"), m.Tipo, m.NomeProduto, m.Quantidade, m.Saldo, m.Motivo)
+}
@@ -0,0 +1,73 @@
+package entidades
+
+import (
+	"fmt"
+	"time"
+)
+
+// TipoMovimentoPontos classifica um movimento de pontos do programa de fidelidade.
+type TipoMovimentoPontos string
+
+const (
+	PontosAcumulo   TipoMovimentoPontos = "acumulo"   // Pontos ganhos em uma venda finalizada.
+	PontosResgate   TipoMovimentoPontos = "resgate"   // Pontos trocados por desconto em uma venda.
+	PontosExpiracao TipoMovimentoPontos = "expiracao" // Pontos vencidos sem uso.
+	PontosEstorno   TipoMovimentoPontos = "estorno"   // Reversão dos pontos de uma venda cancelada ou estornada.
+)
+
+// MovimentoPontos registra uma alteração no saldo de pontos de um cliente.
+// Assim como os movimentos de estoque, os movimentos formam o histórico (extrato) dos
+// pontos: o saldo de um cliente é a soma dos pontos de todos os seus movimentos.
+type MovimentoPontos struct {
+	ID        int64
+	Versao    int64
+	ClienteID int64
+	Tipo      TipoMovimentoPontos
+	Pontos    int64     // Variação do saldo: positiva para créditos, negativa para débitos.
+	Saldo     int64     // Saldo do cliente após o movimento.
+	VendaID   int64     `json:",omitempty"` // Venda que originou o movimento, se houver.
+	Validade  time.Time // Data em que os pontos creditados expiram; zero nos débitos.
+	Motivo    string
+	DataHora  time.Time
+}
+
+// NewMovimentoPontos cria um movimento de pontos com a data e hora atuais.
+func NewMovimentoPontos(clienteID int64, tipo TipoMovimentoPontos, pontos int64, motivo string) *MovimentoPontos {
+	return &MovimentoPontos{
+		ClienteID: clienteID,
+		Tipo:      tipo,
+		Pontos:    pontos,
+		Motivo:    motivo,
+		DataHora:  time.Now(),
+	}
+}
+
+// GetID retorna o ID do MovimentoPontos.
+func (m *MovimentoPontos) GetID() int64 {
+	return m.ID
+}
+
+// SetID define o ID do MovimentoPontos.
+func (m *MovimentoPontos) SetID(id int64) {
+	m.ID = id
+}
+
+// GetVersao retorna a versão do MovimentoPontos.
+func (m *MovimentoPontos) GetVersao() int64 {
+	return m.Versao
+}
+
+// SetVersao define a versão do MovimentoPontos.
+func (m *MovimentoPontos) SetVersao(versao int64) {
+	m.Versao = versao
+}
+
+// String retorna uma representação textual do MovimentoPontos.
+func (m *MovimentoPontos) String() string {
+	var validade string
+	if !m.Validade.IsZero() {
+		validade = " (válidos até " + m.Validade.Format("02/01/2006") + ")"
+	}
+	return fmt.Sprintf("%s %-9s %+8d saldo %8d  %s%s",
+		m.DataHora.Format("2006-01-02 15:04:05"), m.Tipo, m.Pontos, m.Saldo, m.Motivo, validade)
+}
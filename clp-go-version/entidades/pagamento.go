@@ -0,0 +1,167 @@
+package entidades
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FormaPagamento indica como o cliente pagou uma venda, ou parte dela.
+type FormaPagamento string
+
+const (
+	PagamentoDinheiro FormaPagamento = "dinheiro" // Único meio que admite troco.
+	PagamentoDebito   FormaPagamento = "debito"
+	PagamentoCredito  FormaPagamento = "credito" // Admite parcelamento (ver MaxParcelas).
+	PagamentoPix      FormaPagamento = "pix"
+	PagamentoVale     FormaPagamento = "vale" // Vale-alimentação, vale-presente ou crédito de devolução.
+)
+
+// FormasPagamento lista as formas de pagamento aceitas, na ordem em que são oferecidas.
+var FormasPagamento = []FormaPagamento{PagamentoDinheiro, PagamentoDebito, PagamentoCredito, PagamentoPix, PagamentoVale}
+
+// MaxParcelas é a quantidade máxima de parcelas de um pagamento no crédito.
+const MaxParcelas = 12
+
+// ErrPagamentoInsuficiente indica uma venda cujos pagamentos não cobrem o total.
+var ErrPagamentoInsuficiente = errors.New("pagamento insuficiente")
+
+// ParseFormaPagamento interpreta o nome de uma forma de pagamento, sem diferenciar
+// maiúsculas e minúsculas e aceitando os nomes acentuados ("débito", "crédito").
+func ParseFormaPagamento(texto string) (FormaPagamento, error) {
+	texto = strings.ToLower(strings.TrimSpace(texto))
+	texto = strings.NewReplacer("é", "e", "É", "e").Replace(texto)
+	for _, forma := range FormasPagamento {
+		if string(forma) == texto {
+			return forma, nil
+		}
+	}
+	return "", fmt.Errorf("forma de pagamento desconhecida: %q", texto)
+}
+
+// String retorna o nome da forma de pagamento, acentuado, para exibição.
+func (f FormaPagamento) String() string {
+	switch f {
+	case PagamentoDebito:
+		return "débito"
+	case PagamentoCredito:
+		return "crédito"
+	case PagamentoPix:
+		return "Pix"
+	}
+	return string(f)
+}
+
+// Pagamento é uma parte do pagamento de uma venda em uma forma de pagamento.
+// Em dinheiro, Valor é o valor entregue pelo cliente, que pode exceder o restante da venda;
+// a diferença é devolvida como troco (ver Venda.Troco).
+type Pagamento struct {
+	Forma    FormaPagamento
+	Valor    Dinheiro
+	Parcelas int `json:",omitempty"` // Quantidade de parcelas no crédito; zero nas demais formas.
+}
+
+// NewPagamento cria um pagamento à vista na forma informada.
+func NewPagamento(forma FormaPagamento, valor Dinheiro) Pagamento {
+	if forma == PagamentoCredito {
+		return Pagamento{Forma: forma, Valor: valor, Parcelas: 1}
+	}
+	return Pagamento{Forma: forma, Valor: valor}
+}
+
+// NewPagamentoParcelado cria um pagamento no crédito em parcelas.
+func NewPagamentoParcelado(valor Dinheiro, parcelas int) Pagamento {
+	return Pagamento{Forma: PagamentoCredito, Valor: valor, Parcelas: parcelas}
+}
+
+// Validar verifica a forma, o valor e as parcelas do Pagamento.
+func (p Pagamento) Validar() error {
+	if _, err := ParseFormaPagamento(string(p.Forma)); err != nil {
+		return err
+	}
+	if !p.Valor.Positivo() {
+		return errors.New("o valor do pagamento deve ser maior que zero")
+	}
+	if p.Forma == PagamentoCredito {
+		if p.Parcelas < 1 || p.Parcelas > MaxParcelas {
+			return fmt.Errorf("o crédito admite de 1 a %d parcelas", MaxParcelas)
+		}
+	} else if p.Parcelas != 0 {
+		return fmt.Errorf("pagamento em %s não admite parcelas", p.Forma)
+	}
+	return nil
+}
+
+// String retorna a linha do pagamento para a nota fiscal.
+func (p Pagamento) String() string {
+	if p.Parcelas > 1 {
+		parcela := p.Valor.MultiplicarFracao(1, int64(p.Parcelas), ArredondamentoMeioParaCima)
+		return fmt.Sprintf("%-10s %12s (%dx de %s)", p.Forma, p.Valor, p.Parcelas, parcela)
+	}
+	return fmt.Sprintf("%-10s %12s", p.Forma, p.Valor)
+}
+
+// AdicionarPagamento inclui um pagamento na Venda.
+// Apenas pagamentos em dinheiro podem exceder o valor restante, gerando troco.
+func (v *Venda) AdicionarPagamento(p Pagamento) error {
+	if err := p.Validar(); err != nil {
+		return err
+	}
+	restante := v.Restante()
+	if !restante.Positivo() {
+		return errors.New("a venda já está paga")
+	}
+	if p.Forma != PagamentoDinheiro && p.Valor.Comparar(restante) > 0 {
+		return fmt.Errorf("o pagamento em %s de %s excede o restante da venda (%s)", p.Forma, p.Valor, restante)
+	}
+	v.Pagamentos = append(v.Pagamentos, p)
+	return nil
+}
+
+// RemoverPagamentos desfaz todos os pagamentos da Venda.
+func (v *Venda) RemoverPagamentos() {
+	v.Pagamentos = nil
+}
+
+// TotalPago retorna a soma dos pagamentos da Venda, incluindo o valor devolvido como troco.
+func (v *Venda) TotalPago() Dinheiro {
+	total := Reais(0)
+	for _, p := range v.Pagamentos {
+		total = total.Somar(p.Valor)
+	}
+	return total
+}
+
+// Restante retorna quanto falta pagar da Venda, ou zero se os pagamentos já cobrem o total.
+func (v *Venda) Restante() Dinheiro {
+	if restante := v.Total().Subtrair(v.TotalPago()); restante.Positivo() {
+		return restante
+	}
+	return Reais(0)
+}
+
+// Troco retorna o valor a devolver ao cliente, isto é, o quanto os pagamentos excedem o total.
+func (v *Venda) Troco() Dinheiro {
+	if troco := v.TotalPago().Subtrair(v.Total()); troco.Positivo() {
+		return troco
+	}
+	return Reais(0)
+}
+
+// validarPagamentos verifica cada pagamento da Venda e se o troco sai apenas do dinheiro,
+// ou seja, se os pagamentos nas demais formas não excedem o total.
+func (v *Venda) validarPagamentos() error {
+	outros := Reais(0)
+	for _, p := range v.Pagamentos {
+		if err := p.Validar(); err != nil {
+			return err
+		}
+		if p.Forma != PagamentoDinheiro {
+			outros = outros.Somar(p.Valor)
+		}
+	}
+	if outros.Comparar(v.Total()) > 0 {
+		return fmt.Errorf("os pagamentos sem ser em dinheiro (%s) excedem o total da venda (%s)", outros, v.Total())
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package entidades
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPercentualInvalido indica um texto que não pôde ser interpretado como percentual.
+var ErrPercentualInvalido = errors.New("percentual inválido")
+
+// Percentual representa uma porcentagem em ponto fixo, em centésimos de ponto percentual:
+// 1050 equivale a 10,50%. Assim como Dinheiro, evita os erros de arredondamento de float64.
+type Percentual int64
+
+// CemPorCento é o Percentual equivalente a 100%.
+const CemPorCento Percentual = 10000
+
+// ParsePercentual interpreta um percentual digitado pelo usuário, como "10", "10,5" ou "7.25%".
+// São aceitas até duas casas decimais.
+func ParsePercentual(texto string) (Percentual, error) {
+	s := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(texto), "%"))
+	inteiro, fracao, ok := separarDecimal(s)
+	if !ok || inteiro == "" && fracao == "" {
+		return 0, fmt.Errorf("%w: %q", ErrPercentualInvalido, texto)
+	}
+	if inteiro == "" {
+		inteiro = "0"
+	}
+	for len(fracao) < 2 {
+		fracao += "0"
+	}
+	valor, err := strconv.ParseInt(inteiro+fracao, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrPercentualInvalido, texto)
+	}
+	return Percentual(valor), nil
+}
+
+// Aplicar retorna o percentual p do valor informado, arredondado para centavos.
+func (p Percentual) Aplicar(valor Dinheiro, modo ModoArredondamento) Dinheiro {
+	return valor.MultiplicarFracao(int64(p), int64(CemPorCento), modo)
+}
+
+// String retorna o percentual no padrão brasileiro, como "10,50%".
+func (p Percentual) String() string {
+	sinal, v := "", int64(p)
+	if v < 0 {
+		sinal, v = "-", -v
+	}
+	return fmt.Sprintf("%s%d,%02d%%", sinal, v/100, v%100)
+}
+
+// ProporcaoPercentual calcula quanto parte representa de todo, arredondando para o
+// centésimo de ponto mais próximo. Retorna zero quando todo não é positivo.
+func ProporcaoPercentual(parte, todo Dinheiro) Percentual {
+	if !todo.Positivo() {
+		return 0
+	}
+	return Percentual(dividir(parte.Centavos*int64(CemPorCento), todo.Centavos, ArredondamentoMeioParaCima))
+}
@@ -1,35 +1,47 @@
 package entidades
 
 import (
-	"fmt"  // O pacote `fmt` é usado para formatação e saída de strings.
-	"time" // O pacote `time` fornece funcionalidades para trabalhar com data e hora.
+	"errors"  // O pacote `errors` é usado para criar valores de erro.
+	"fmt"     // O pacote `fmt` é usado para formatação e saída de strings.
+	"strings" // O pacote `strings` oferece funções para manipular textos.
 )
 
 // Produto representa um produto com nome e valor.
 // Em Go, structs são usadas para agrupar campos relacionados. São semelhantes a classes em outras linguagens,
 // mas Go não possui herança. Em vez disso, utiliza composição para reutilização de código.
 type Produto struct {
-	ID    int64   // Campo para armazenar o identificador único do produto. Aqui utilizamos `int64` para garantir precisão.
-	Nome  string  // Nome do produto, armazenado como uma string.
-	Valor float64 // Valor do produto, representado com precisão decimal usando o tipo `float64`.
+	ID        int64    // Campo para armazenar o identificador único do produto. Aqui utilizamos `int64` para garantir precisão.
+	Nome      string   // Nome do produto, armazenado como uma string.
+	SKU       string   `json:",omitempty"` // Código do produto (Stock Keeping Unit). Opcional, mas único quando informado.
+	Valor     Dinheiro // Valor do produto em ponto fixo (centavos), evitando os erros de arredondamento do `float64`.
+	Estoque   int      // Quantidade disponível em estoque, alterada pelos movimentos de estoque.
+	Versao    int64    // Versão do registro, usada pelo DAO para rejeitar atualizações concorrentes.
+	NCM       string   `json:",omitempty"` // Nomenclatura Comum do Mercosul (8 dígitos), usada no cálculo dos tributos.
+	CFOP      string   `json:",omitempty"` // Código Fiscal de Operações (4 dígitos); 5933 e 6933 indicam serviço sujeito ao ISS.
+	Categoria string   `json:",omitempty"` // Categoria do produto, usada pelas promoções por categoria.
 }
 
 // ItemVenda representa um item em uma venda.
 // Essa struct usa composição para relacionar um produto a uma venda, incluindo a quantidade e o valor total.
 type ItemVenda struct {
-	Produto    Produto // Um campo que referencia a struct Produto.
-	Quantidade int     // Número de unidades do produto na venda.
-	Valor      float64 // Valor total desse item, calculado como `Quantidade * Produto.Valor`.
+	Produto    Produto        // Um campo que referencia a struct Produto.
+	Quantidade int            // Número de unidades do produto na venda.
+	Valor      Dinheiro       // Valor unitário do produto no momento da venda; o total do item é calculado por `Total`.
+	Desconto   Desconto       // Desconto concedido pelo operador, aplicado sobre o valor promocional.
+	Tributos   []ValorTributo `json:",omitempty"` // Tributos aproximados, calculados na finalização da venda.
+
+	Promocoes        []string `json:",omitempty"` // Nomes das promoções aplicadas ao item (ver Venda.AplicarPromocoes).
+	DescontoPromocao Dinheiro // Desconto total das promoções aplicadas ao item.
 }
 
 // NewProduto cria um novo Produto com valores padrão.
 // Funções iniciadas com "New" são convenções em Go para criar e inicializar structs.
 // Essa função retorna um ponteiro para um novo Produto.
-func NewProduto(nome string, valor float64) *Produto {
+// O ID fica zerado até que o produto seja adicionado a um DAO, que atribui um ID único.
+func NewProduto(nome string, valor Dinheiro) *Produto {
 	return &Produto{
-		ID:    time.Now().UnixMilli(), // Gera um ID único usando o timestamp em milissegundos.
-		Nome:  nome,                   // Inicializa o campo Nome com o valor fornecido.
-		Valor: valor,                  // Inicializa o campo Valor com o valor fornecido.
+		Nome:  nome,  // Inicializa o campo Nome com o valor fornecido.
+		Valor: valor, // Inicializa o campo Valor com o valor fornecido.
 	}
 }
 
@@ -41,10 +53,67 @@ func (p *Produto) GetID() int64 {
 	return p.ID // Retorna o identificador único do produto.
 }
 
+// SetID define o ID do Produto.
+// Aqui o receptor precisa ser um ponteiro, pois o método modifica o próprio Produto.
+func (p *Produto) SetID(id int64) {
+	p.ID = id
+}
+
+// GetVersao retorna a versão do Produto.
+func (p *Produto) GetVersao() int64 {
+	return p.Versao
+}
+
+// SetVersao define a versão do Produto.
+func (p *Produto) SetVersao(versao int64) {
+	p.Versao = versao
+}
+
+// Validar verifica se o Produto tem nome e valor positivo.
+// Este método implementa a interface Validavel, usada pelo DAO antes de gravar o produto.
+func (p *Produto) Validar() error {
+	if strings.TrimSpace(p.Nome) == "" {
+		return errors.New("o nome do produto é obrigatório")
+	}
+	if !p.Valor.Positivo() {
+		return errors.New("o valor do produto deve ser maior que zero")
+	}
+	if p.Estoque < 0 {
+		return errors.New("o estoque do produto não pode ser negativo")
+	}
+	if p.NCM != "" && (len(p.NCM) != 8 || !apenasDigitos(p.NCM)) {
+		return fmt.Errorf("NCM inválido: %q (deve ter 8 dígitos)", p.NCM)
+	}
+	if p.CFOP != "" && (len(p.CFOP) != 4 || !apenasDigitos(p.CFOP)) {
+		return fmt.Errorf("CFOP inválido: %q (deve ter 4 dígitos)", p.CFOP)
+	}
+	return nil
+}
+
+// Clonar retorna uma cópia do Produto.
+// A cópia pode ser editada livremente e depois enviada ao DAO para atualização.
+func (p *Produto) Clonar() *Produto {
+	copia := *p // Atribuir uma struct a outra variável copia todos os seus campos.
+	return &copia
+}
+
 // String retorna uma representação textual do Produto.
 // Esse método implementa a interface `fmt.Stringer`, o que permite formatar um Produto em strings personalizadas.
 func (p *Produto) String() string {
-	return fmt.Sprintf("Produto[ID=%d, Nome=%s, Valor=%.2f]", p.ID, p.Nome, p.Valor)
+	var fiscal string // Classificação fiscal e categoria, exibidas apenas quando informadas.
+	if p.NCM != "" {
+		fiscal += ", NCM=" + p.NCM
+	}
+	if p.CFOP != "" {
+		fiscal += ", CFOP=" + p.CFOP
+	}
+	if p.Categoria != "" {
+		fiscal += ", Categoria=" + p.Categoria
+	}
+	if p.SKU != "" {
+		return fmt.Sprintf("Produto[ID=%d, SKU=%s, Nome=%s, Valor=%s, Estoque=%d%s]", p.ID, p.SKU, p.Nome, p.Valor, p.Estoque, fiscal)
+	}
+	return fmt.Sprintf("Produto[ID=%d, Nome=%s, Valor=%s, Estoque=%d%s]", p.ID, p.Nome, p.Valor, p.Estoque, fiscal)
 }
 
 // GetNome retorna o nome do Produto.
@@ -55,10 +124,66 @@ func (p *Produto) GetNome() string {
 
 // GetValor retorna o valor do Produto.
 // Em Go, a abordagem de encapsulamento é opcional, mas pode ser usada para garantir consistência ou validação.
-func (p *Produto) GetValor() float64 {
+func (p *Produto) GetValor() Dinheiro {
 	return p.Valor
 }
 
+// GetEstoque retorna a quantidade do Produto disponível em estoque.
+func (p *Produto) GetEstoque() int {
+	return p.Estoque
+}
+
+// SetEstoque define a quantidade em estoque.
+// O estoque deve ser alterado por meio de movimentos de estoque, que registram o histórico.
+func (p *Produto) SetEstoque(quantidade int) {
+	p.Estoque = quantidade
+}
+
+// GetNCM retorna a classificação NCM do Produto, ou "" se não houver.
+func (p *Produto) GetNCM() string {
+	return p.NCM
+}
+
+// SetNCM define a classificação NCM do Produto. Pontos são removidos ("1006.30.21" vira "10063021").
+func (p *Produto) SetNCM(ncm string) {
+	p.NCM = strings.ReplaceAll(strings.TrimSpace(ncm), ".", "")
+}
+
+// GetCFOP retorna o CFOP do Produto, ou "" se não houver.
+func (p *Produto) GetCFOP() string {
+	return p.CFOP
+}
+
+// SetCFOP define o CFOP do Produto. Pontos são removidos ("5.102" vira "5102").
+func (p *Produto) SetCFOP(cfop string) {
+	p.CFOP = strings.ReplaceAll(strings.TrimSpace(cfop), ".", "")
+}
+
+// Servico informa se o Produto é um serviço sujeito ao ISS em vez do ICMS, conforme o CFOP.
+func (p *Produto) Servico() bool {
+	return p.CFOP == "5933" || p.CFOP == "6933"
+}
+
+// GetCategoria retorna a categoria do Produto, ou "" se não houver.
+func (p *Produto) GetCategoria() string {
+	return p.Categoria
+}
+
+// SetCategoria define a categoria do Produto.
+func (p *Produto) SetCategoria(categoria string) {
+	p.Categoria = strings.TrimSpace(categoria)
+}
+
+// GetSKU retorna o código (SKU) do Produto, ou "" se não houver.
+func (p *Produto) GetSKU() string {
+	return p.SKU
+}
+
+// SetSKU define o código (SKU) do Produto.
+func (p *Produto) SetSKU(sku string) {
+	p.SKU = sku
+}
+
 // SetNome define o nome do Produto.
 // Métodos `Set` permitem modificar campos de uma struct de forma controlada.
 // Aqui estamos aceitando diretamente o valor sem validação.
@@ -69,10 +194,33 @@ func (p *Produto) SetNome(nome string) {
 // SetValor define o valor do Produto.
 // Este método é semelhante ao `SetNome`, mas modifica o campo Valor.
 // Ele poderia ser estendido para incluir validações, como verificar se o valor não é negativo.
-func (p *Produto) SetValor(valor float64) {
+func (p *Produto) SetValor(valor Dinheiro) {
 	p.Valor = valor // Atualiza o campo Valor com o valor fornecido.
 }
 
+// Bruto retorna o valor unitário multiplicado pela quantidade, antes do desconto.
+func (i ItemVenda) Bruto() Dinheiro {
+	return i.Valor.Multiplicar(int64(i.Quantidade))
+}
+
+// ValorPromocional retorna o valor bruto menos o desconto das promoções.
+func (i ItemVenda) ValorPromocional() Dinheiro {
+	return i.Bruto().Subtrair(i.DescontoPromocao)
+}
+
+// ValorDesconto retorna o valor do desconto concedido pelo operador no item.
+func (i ItemVenda) ValorDesconto() Dinheiro {
+	return i.Desconto.Calcular(i.ValorPromocional())
+}
+
+// Total retorna o valor do item, isto é, o valor promocional menos o desconto do item.
+// Com isso, ItemVenda também implementa a interface Totalizavel.
+func (i ItemVenda) Total() Dinheiro {
+	return i.ValorPromocional().Subtrair(i.ValorDesconto())
+}
+
+// String retorna a linha do item formatada para a nota fiscal, com o valor bruto.
+// O desconto do item, se houver, é exibido pela Venda em uma linha própria.
 func (i ItemVenda) String() string {
-	return fmt.Sprintf("%15s %8.2f x %5d = %8.2f", i.Produto.GetNome(), i.Valor, i.Quantidade, i.Valor*float64(i.Quantidade))
+	return fmt.Sprintf("%15s %12s x %5d = %12s", i.Produto.GetNome(), i.Valor, i.Quantidade, i.Bruto())
 }
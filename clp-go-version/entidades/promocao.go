@@ -0,0 +1,309 @@
+package entidades
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TipoPromocao define como uma promoção calcula o seu desconto.
+type TipoPromocao string
+
+const (
+	// PromocaoLevePague dá, a cada Leve unidades de um mesmo produto, Leve-Pague unidades de graça.
+	PromocaoLevePague TipoPromocao = "leve_pague"
+	// PromocaoCombo vende juntos uma unidade de cada produto de ProdutoIDs por PrecoCombo.
+	PromocaoCombo TipoPromocao = "combo"
+	// PromocaoDesconto aplica Desconto aos itens abrangidos; um desconto fixo vale por unidade.
+	PromocaoDesconto TipoPromocao = "desconto"
+)
+
+// formatoHora é o formato dos horários de vigência das promoções.
+const formatoHora = "15:04"
+
+// Promocao é uma regra de preço aplicada automaticamente às vendas (ver Venda.AplicarPromocoes).
+//
+// A promoção abrange os produtos de ProdutoIDs e os da Categoria; sem nenhum dos dois,
+// abrange todos os produtos (exceto no combo, que exige os produtos). Com Inicio e Fim
+// preenchidos ("18:00" e "20:00"), vale apenas nesse horário, como em um happy hour;
+// um Fim anterior ao Inicio atravessa a meia-noite.
+//
+// As promoções são avaliadas da maior para a menor Prioridade. Um item pode receber várias
+// promoções cumulativas; uma promoção não cumulativa só é aplicada a itens que ainda não
+// receberam promoção e impede que eles recebam outras.
+type Promocao struct {
+	ID         int64
+	Versao     int64
+	Nome       string
+	Tipo       TipoPromocao
+	Ativa      bool
+	Prioridade int
+	Cumulativa bool
+
+	ProdutoIDs []int64 `json:",omitempty"`
+	Categoria  string  `json:",omitempty"`
+	Inicio     string  `json:",omitempty"` // Início da vigência diária, "HH:MM".
+	Fim        string  `json:",omitempty"` // Fim da vigência diária, "HH:MM", exclusivo.
+
+	Leve       int      `json:",omitempty"` // Leve e pague.
+	Pague      int      `json:",omitempty"` // Leve e pague.
+	PrecoCombo Dinheiro // Combo.
+	Desconto   Desconto // Desconto.
+}
+
+// NewPromocao cria uma promoção ativa, ainda sem regra definida.
+// O ID é atribuído pelo DAO quando a promoção é adicionada.
+func NewPromocao(nome string, tipo TipoPromocao) *Promocao {
+	return &Promocao{Nome: strings.TrimSpace(nome), Tipo: tipo, Ativa: true}
+}
+
+// GetID retorna o ID da Promocao.
+func (p *Promocao) GetID() int64 {
+	return p.ID
+}
+
+// SetID define o ID da Promocao.
+func (p *Promocao) SetID(id int64) {
+	p.ID = id
+}
+
+// GetVersao retorna a versão da Promocao.
+func (p *Promocao) GetVersao() int64 {
+	return p.Versao
+}
+
+// SetVersao define a versão da Promocao.
+func (p *Promocao) SetVersao(versao int64) {
+	p.Versao = versao
+}
+
+// Clonar retorna uma cópia da Promocao.
+func (p *Promocao) Clonar() *Promocao {
+	copia := *p
+	copia.ProdutoIDs = slices.Clone(p.ProdutoIDs)
+	return &copia
+}
+
+// Validar verifica se a Promocao tem nome, horários válidos e os parâmetros do seu tipo.
+func (p *Promocao) Validar() error {
+	if p.Nome == "" {
+		return errors.New("o nome da promoção é obrigatório")
+	}
+	if (p.Inicio == "") != (p.Fim == "") {
+		return errors.New("informe o início e o fim do horário, ou nenhum dos dois")
+	}
+	for _, hora := range []string{p.Inicio, p.Fim} {
+		if _, err := time.Parse(formatoHora, hora); hora != "" && err != nil {
+			return fmt.Errorf("horário inválido: %q (use HH:MM)", hora)
+		}
+	}
+
+	switch p.Tipo {
+	case PromocaoLevePague:
+		if p.Pague < 1 || p.Leve <= p.Pague {
+			return fmt.Errorf("leve %d pague %d: a quantidade paga deve ser positiva e menor que a levada", p.Leve, p.Pague)
+		}
+	case PromocaoCombo:
+		distintos := slices.Clone(p.ProdutoIDs)
+		slices.Sort(distintos)
+		if len(p.ProdutoIDs) < 2 || len(slices.Compact(distintos)) != len(p.ProdutoIDs) {
+			return errors.New("o combo precisa de ao menos dois produtos diferentes")
+		}
+		if !p.PrecoCombo.Positivo() {
+			return errors.New("o preço do combo deve ser maior que zero")
+		}
+	case PromocaoDesconto:
+		if p.Desconto.Vazio() {
+			return errors.New("informe o desconto da promoção")
+		}
+		if err := p.Desconto.Validar(p.Desconto.Valor); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("tipo de promoção desconhecido: %q", p.Tipo)
+	}
+	return nil
+}
+
+// Vigente informa se a Promocao está ativa e dentro do seu horário no instante informado.
+func (p *Promocao) Vigente(agora time.Time) bool {
+	if !p.Ativa {
+		return false
+	}
+	if p.Inicio == "" {
+		return true
+	}
+	hora := agora.Format(formatoHora)
+	if p.Inicio <= p.Fim {
+		return p.Inicio <= hora && hora < p.Fim
+	}
+	return hora >= p.Inicio || hora < p.Fim // Atravessa a meia-noite.
+}
+
+// Abrange informa se o produto está entre os abrangidos pela Promocao.
+func (p *Promocao) Abrange(produto *Produto) bool {
+	if len(p.ProdutoIDs) == 0 && p.Categoria == "" {
+		return p.Tipo != PromocaoCombo
+	}
+	return slices.Contains(p.ProdutoIDs, produto.GetID()) ||
+		p.Categoria != "" && strings.EqualFold(p.Categoria, produto.GetCategoria())
+}
+
+// String retorna uma representação textual da Promocao.
+func (p *Promocao) String() string {
+	var regra string
+	switch p.Tipo {
+	case PromocaoLevePague:
+		regra = fmt.Sprintf("leve %d pague %d", p.Leve, p.Pague)
+	case PromocaoCombo:
+		regra = fmt.Sprintf("combo por %s", p.PrecoCombo)
+	case PromocaoDesconto:
+		regra = fmt.Sprintf("desconto de %s", p.Desconto)
+	}
+	situacao := "ativa"
+	if !p.Ativa {
+		situacao = "inativa"
+	}
+	horario := ""
+	if p.Inicio != "" {
+		horario = fmt.Sprintf(", das %s às %s", p.Inicio, p.Fim)
+	}
+	cumulativa := "não cumulativa"
+	if p.Cumulativa {
+		cumulativa = "cumulativa"
+	}
+	return fmt.Sprintf("Promocao[ID=%d, Nome=%s, %s, prioridade %d, %s, %s%s]", p.ID, p.Nome, regra, p.Prioridade, cumulativa, situacao, horario)
+}
+
+// AplicarPromocoes recalcula as promoções de todos os itens da Venda, considerando as
+// promoções vigentes no instante informado. Deve ser chamado sempre que os itens mudam,
+// pois promoções como leve e pague e combo dependem do conjunto de itens.
+func (v *Venda) AplicarPromocoes(promocoes []*Promocao, agora time.Time) {
+	for i := range v.Itens {
+		v.Itens[i].Promocoes = nil
+		v.Itens[i].DescontoPromocao = Dinheiro{}
+	}
+
+	vigentes := slices.DeleteFunc(slices.Clone(promocoes), func(p *Promocao) bool { return !p.Vigente(agora) })
+	slices.SortStableFunc(vigentes, func(a, b *Promocao) int {
+		return cmp.Or(cmp.Compare(b.Prioridade, a.Prioridade), cmp.Compare(a.ID, b.ID))
+	})
+
+	bloqueados := make([]bool, len(v.Itens)) // Itens que receberam promoção não cumulativa.
+	for _, p := range vigentes {
+		elegiveis := []int{}
+		for i := range v.Itens {
+			item := &v.Itens[i]
+			if bloqueados[i] || !p.Cumulativa && len(item.Promocoes) > 0 || !p.Abrange(&item.Produto) {
+				continue
+			}
+			elegiveis = append(elegiveis, i)
+		}
+
+		descontos := p.calcular(v.Itens, elegiveis)
+		for i, desconto := range descontos {
+			item := &v.Itens[i]
+			// O desconto das promoções nunca passa do valor bruto do item.
+			if restante := item.ValorPromocional(); desconto.Comparar(restante) > 0 {
+				desconto = restante
+			}
+			if !desconto.Positivo() {
+				continue
+			}
+			item.DescontoPromocao = item.DescontoPromocao.Somar(desconto)
+			item.Promocoes = append(item.Promocoes, p.Nome)
+			if !p.Cumulativa {
+				bloqueados[i] = true
+			}
+		}
+	}
+}
+
+// calcular retorna o desconto da promoção para cada item elegível, pela posição do item.
+func (p *Promocao) calcular(itens []ItemVenda, elegiveis []int) map[int]Dinheiro {
+	descontos := map[int]Dinheiro{}
+	switch p.Tipo {
+	case PromocaoLevePague:
+		// A cada Leve unidades de um produto, Leve-Pague saem de graça, começando pelas mais baratas.
+		porProduto := map[int64][]int{}
+		for _, i := range elegiveis {
+			porProduto[itens[i].Produto.GetID()] = append(porProduto[itens[i].Produto.GetID()], i)
+		}
+		for _, posicoes := range porProduto {
+			quantidade := 0
+			for _, i := range posicoes {
+				quantidade += itens[i].Quantidade
+			}
+			gratis := quantidade / p.Leve * (p.Leve - p.Pague)
+			slices.SortStableFunc(posicoes, func(a, b int) int { return itens[a].Valor.Comparar(itens[b].Valor) })
+			for _, i := range posicoes {
+				unidades := min(gratis, itens[i].Quantidade)
+				if unidades > 0 {
+					descontos[i] = itens[i].Valor.Multiplicar(int64(unidades))
+				}
+				gratis -= unidades
+			}
+		}
+
+	case PromocaoCombo:
+		// Forma quantos combos couberem e divide o desconto de cada combo entre os produtos,
+		// na proporção dos seus valores. O desconto de um produto vendido em várias linhas é
+		// distribuído entre elas, sem passar do valor de cada linha.
+		quantidades := map[int64]int{}
+		posicoes := map[int64][]int{} // Itens de cada produto; o primeiro é a referência do valor.
+		for _, i := range elegiveis {
+			id := itens[i].Produto.GetID()
+			posicoes[id] = append(posicoes[id], i)
+			quantidades[id] += itens[i].Quantidade
+		}
+		combos := -1
+		normal := Reais(0)
+		for _, id := range p.ProdutoIDs {
+			if len(posicoes[id]) == 0 {
+				return descontos
+			}
+			if combos < 0 || quantidades[id] < combos {
+				combos = quantidades[id]
+			}
+			normal = normal.Somar(itens[posicoes[id][0]].Valor)
+		}
+		economia := normal.Subtrair(p.PrecoCombo)
+		if combos <= 0 || !economia.Positivo() {
+			return descontos
+		}
+
+		restante := economia
+		for n, id := range p.ProdutoIDs {
+			parte := economia.MultiplicarFracao(itens[posicoes[id][0]].Valor.Centavos, normal.Centavos, ArredondamentoMeioParaCima)
+			if n == len(p.ProdutoIDs)-1 {
+				parte = restante // O último produto fica com a sobra do arredondamento.
+			}
+			restante = restante.Subtrair(parte)
+			total := parte.Multiplicar(int64(combos))
+			for _, i := range posicoes[id] {
+				desconto := total
+				if limite := itens[i].ValorPromocional(); desconto.Comparar(limite) > 0 {
+					desconto = limite
+				}
+				if desconto.Positivo() {
+					descontos[i] = desconto
+				}
+				total = total.Subtrair(desconto)
+			}
+		}
+
+	case PromocaoDesconto:
+		for _, i := range elegiveis {
+			item := itens[i]
+			if p.Desconto.Percentual != 0 {
+				descontos[i] = p.Desconto.Calcular(item.ValorPromocional())
+			} else {
+				descontos[i] = p.Desconto.Valor.Multiplicar(int64(item.Quantidade))
+			}
+		}
+	}
+	return descontos
+}
@@ -0,0 +1,35 @@
+package entidades
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComboDistribuiDescontoEntreLinhasDoProduto(t *testing.T) {
+	a := NewProduto("Lanche", Reais(1000))
+	a.SetID(1)
+	b := NewProduto("Refrigerante", Reais(1000))
+	b.SetID(2)
+	combo := NewPromocao("Combo", PromocaoCombo)
+	combo.ProdutoIDs = []int64{1, 2}
+	combo.PrecoCombo = Reais(200)
+
+	// O lanche foi incluído em duas linhas de uma unidade cada; o refrigerante, em uma
+	// linha com duas unidades. Formam-se dois combos com economia de R$ 18,00 cada,
+	// R$ 9,00 por produto.
+	venda := NewVenda()
+	venda.AdicionarItem(*a, 1)
+	venda.AdicionarItem(*b, 2)
+	venda.AdicionarItem(*a, 1)
+	venda.AplicarPromocoes([]*Promocao{combo}, time.Now())
+
+	esperados := []Dinheiro{Reais(1000), Reais(1800), Reais(800)}
+	for i, esperado := range esperados {
+		if item := venda.GetItens()[i]; item.DescontoPromocao != esperado {
+			t.Errorf("item %d (%s): desconto %s, esperado %s", i+1, item.Produto.GetNome(), item.DescontoPromocao, esperado)
+		}
+	}
+	if total, esperado := venda.Total(), Reais(400); total != esperado {
+		t.Errorf("total da venda %s, esperado %s (dois combos)", total, esperado)
+	}
+}
@@ -0,0 +1,62 @@
+package entidades
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StatusVenda indica em que etapa do seu ciclo de vida está uma venda.
+//
+// Uma venda começa aberta, quando seus itens ainda podem ser alterados, e é finalizada
+// ao ser concluída. Uma venda aberta ou finalizada pode ser cancelada; somente uma venda
+// finalizada pode ser estornada, devolvendo o valor ao cliente. Vendas canceladas e
+// estornadas são mantidas no histórico, mas não entram nos totais de faturamento.
+type StatusVenda string
+
+const (
+	VendaAberta     StatusVenda = "aberta"     // Em andamento; os itens podem ser alterados.
+	VendaFinalizada StatusVenda = "finalizada" // Concluída.
+	VendaCancelada  StatusVenda = "cancelada"  // Anulada antes ou logo após a conclusão.
+	VendaEstornada  StatusVenda = "estornada"  // Concluída e depois desfeita, com devolução do valor.
+)
+
+// transicoesVenda relaciona, para cada status, os status que podem sucedê-lo.
+var transicoesVenda = map[StatusVenda][]StatusVenda{
+	VendaAberta:     {VendaFinalizada, VendaCancelada},
+	VendaFinalizada: {VendaCancelada, VendaEstornada},
+}
+
+// ErrTransicaoInvalida indica uma mudança de status não permitida pelo ciclo de vida da venda.
+var ErrTransicaoInvalida = errors.New("transição de status inválida")
+
+// ErroTransicao detalha uma mudança de status recusada.
+// É equivalente a ErrTransicaoInvalida para errors.Is.
+type ErroTransicao struct {
+	De   StatusVenda
+	Para StatusVenda
+}
+
+// Error implementa a interface error.
+func (e *ErroTransicao) Error() string {
+	return fmt.Sprintf("%v: uma venda %s não pode ser %s", ErrTransicaoInvalida, e.De, e.Para)
+}
+
+// Is faz com que errors.Is(err, ErrTransicaoInvalida) seja verdadeiro para um *ErroTransicao.
+func (e *ErroTransicao) Is(alvo error) bool {
+	return alvo == ErrTransicaoInvalida
+}
+
+// PodeMudarPara informa se o ciclo de vida permite passar do status s para o status novo.
+func (s StatusVenda) PodeMudarPara(novo StatusVenda) bool {
+	for _, permitido := range transicoesVenda[s] {
+		if permitido == novo {
+			return true
+		}
+	}
+	return false
+}
+
+// Contabilizada informa se as vendas com este status entram nos totais de faturamento.
+func (s StatusVenda) Contabilizada() bool {
+	return s == VendaFinalizada
+}
@@ -0,0 +1,106 @@
+package entidades
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tributo identifica um tributo incidente sobre as vendas.
+type Tributo string
+
+const (
+	ICMS   Tributo = "ICMS"   // Imposto estadual sobre circulação de mercadorias.
+	PIS    Tributo = "PIS"    // Contribuição federal para o Programa de Integração Social.
+	COFINS Tributo = "COFINS" // Contribuição federal para o financiamento da seguridade social.
+	ISS    Tributo = "ISS"    // Imposto municipal sobre serviços, no lugar do ICMS.
+)
+
+// ValorTributo é o valor de um tributo calculado sobre um item.
+type ValorTributo struct {
+	Tributo  Tributo
+	Aliquota Percentual
+	Base     Dinheiro // Valor do item sobre o qual a alíquota foi aplicada.
+	Valor    Dinheiro
+}
+
+// RegraTributaria calcula os tributos de um item de venda.
+// A base informada é o valor efetivamente pago pelo item, já descontados os descontos.
+// Implementações diferentes permitem trocar a forma de cálculo (por exemplo, por regime
+// tributário) sem alterar a venda.
+type RegraTributaria interface {
+	Calcular(item ItemVenda, base Dinheiro) []ValorTributo
+}
+
+// Aliquotas associa cada tributo à sua alíquota.
+type Aliquotas map[Tributo]Percentual
+
+// AliquotasEstado reúne as alíquotas de um estado: as padrão e as específicas de
+// classificações NCM, indicadas por prefixo ("1006" vale para todo NCM iniciado por 1006).
+// As alíquotas de um prefixo substituem as padrão apenas para os tributos que ele informa.
+type AliquotasEstado struct {
+	Padrao Aliquotas
+	PorNCM map[string]Aliquotas
+}
+
+// RegraPorEstado é a RegraTributaria que aplica as alíquotas do estado da loja.
+// Mercadorias pagam ICMS, PIS e COFINS; serviços (ver Produto.Servico) pagam ISS no lugar
+// do ICMS. Para cada tributo vale a alíquota do prefixo NCM mais longo que a informe.
+type RegraPorEstado struct {
+	UF        string
+	Aliquotas AliquotasEstado
+}
+
+// Calcular implementa RegraTributaria.
+func (r RegraPorEstado) Calcular(item ItemVenda, base Dinheiro) []ValorTributo {
+	tributos := []Tributo{ICMS, PIS, COFINS}
+	if item.Produto.Servico() {
+		tributos = []Tributo{ISS, PIS, COFINS}
+	}
+
+	valores := []ValorTributo{}
+	for _, tributo := range tributos {
+		aliquota := r.aliquota(item.Produto.GetNCM(), tributo)
+		if aliquota == 0 {
+			continue
+		}
+		valores = append(valores, ValorTributo{
+			Tributo:  tributo,
+			Aliquota: aliquota,
+			Base:     base,
+			Valor:    aliquota.Aplicar(base, ArredondamentoMeioParaCima),
+		})
+	}
+	return valores
+}
+
+// aliquota retorna a alíquota do tributo para o NCM, pelo prefixo mais longo que a informe.
+func (r RegraPorEstado) aliquota(ncm string, tributo Tributo) Percentual {
+	aliquota, tamanho := r.Aliquotas.Padrao[tributo], -1
+	for prefixo, especificas := range r.Aliquotas.PorNCM {
+		valor, ok := especificas[tributo]
+		if ok && strings.HasPrefix(ncm, prefixo) && len(prefixo) > tamanho {
+			aliquota, tamanho = valor, len(prefixo)
+		}
+	}
+	return aliquota
+}
+
+// SomarTributos soma os valores por tributo, na ordem ICMS, ISS, PIS e COFINS.
+func SomarTributos(valores []ValorTributo) []ValorTributo {
+	ordem := []Tributo{ICMS, ISS, PIS, COFINS}
+	totais := map[Tributo]Dinheiro{}
+	for _, v := range valores {
+		totais[v.Tributo] = totais[v.Tributo].Somar(v.Valor)
+		if !slices.Contains(ordem, v.Tributo) {
+			ordem = append(ordem, v.Tributo)
+		}
+	}
+
+	soma := []ValorTributo{}
+	for _, tributo := range ordem {
+		if valor, ok := totais[tributo]; ok {
+			soma = append(soma, ValorTributo{Tributo: tributo, Valor: valor})
+		}
+	}
+	return soma
+}
@@ -1,24 +1,42 @@
 package entidades
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
 
-// Venda representa uma venda com data, hora e itens.
+// Venda representa uma venda com data, hora, itens e status (ver StatusVenda).
+// As mudanças de status são feitas por Finalizar, Cancelar e Estornar, que recusam
+// as transições não permitidas pelo ciclo de vida da venda.
 type Venda struct {
-	ID       int64
-	DataHora time.Time
-	Itens    []ItemVenda
+	ID                 int64
+	Versao             int64
+	DataHora           time.Time // Abertura da venda.
+	Itens              []ItemVenda
+	Status             StatusVenda
+	FinalizadaEm       time.Time
+	CanceladaEm        time.Time   // Data e hora do cancelamento ou do estorno.
+	MotivoCancelamento string      `json:",omitempty"` // Motivo do cancelamento ou do estorno.
+	Desconto           Desconto    // Desconto no total da venda, aplicado sobre o subtotal.
+	Operador           string      `json:",omitempty"` // Operador que concedeu os descontos.
+	ClienteID          int64       `json:",omitempty"` // Cliente identificado na venda, se houver.
+	NomeCliente        string      `json:",omitempty"` // Nome do cliente na data da venda.
+	PontosResgatados   int64       `json:",omitempty"` // Pontos de fidelidade do cliente trocados por desconto.
+	ValorResgate       Dinheiro    // Desconto obtido com os pontos resgatados, aplicado após o desconto da venda.
+	Pagamentos         []Pagamento `json:",omitempty"` // Formas em que o cliente pagou a venda.
+	CaixaID            int64       `json:",omitempty"` // Sessão de caixa em que a venda foi registrada e, depois, finalizada.
 }
 
-// NewVenda cria uma nova instância de Venda.
+// NewVenda cria uma nova instância de Venda, aberta.
+// O ID é atribuído pelo DAO quando a venda é adicionada.
 func NewVenda() *Venda {
 	return &Venda{
-		ID:       time.Now().UnixMilli(),
 		DataHora: time.Now(),
 		Itens:    []ItemVenda{},
+		Status:   VendaAberta,
 	}
 }
 
@@ -27,15 +45,215 @@ func (v *Venda) GetID() int64 {
 	return v.ID
 }
 
+// SetID define o ID da Venda.
+func (v *Venda) SetID(id int64) {
+	v.ID = id
+}
+
+// GetVersao retorna a versão da Venda.
+func (v *Venda) GetVersao() int64 {
+	return v.Versao
+}
+
+// SetVersao define a versão da Venda.
+func (v *Venda) SetVersao(versao int64) {
+	v.Versao = versao
+}
+
+// SetCliente identifica o cliente da Venda. Com nil, a venda fica sem cliente.
+// Trocar o cliente desfaz o resgate de pontos, que pertencem ao cliente anterior.
+func (v *Venda) SetCliente(cliente *Cliente) {
+	var id int64
+	if cliente != nil {
+		id = cliente.GetID()
+	}
+	if id != v.ClienteID {
+		v.PontosResgatados, v.ValorResgate = 0, Dinheiro{}
+	}
+	if cliente == nil {
+		v.ClienteID, v.NomeCliente = 0, ""
+		return
+	}
+	v.ClienteID, v.NomeCliente = cliente.GetID(), cliente.GetNome()
+}
+
+// ResgatarPontos troca pontos de fidelidade do cliente da Venda pelo valor informado,
+// descontado do total após o desconto da venda. Com zero pontos, o resgate é desfeito.
+// O saldo do cliente é conferido e debitado apenas na finalização da venda.
+func (v *Venda) ResgatarPontos(pontos int64, valor Dinheiro) error {
+	if pontos == 0 {
+		v.PontosResgatados, v.ValorResgate = 0, Dinheiro{}
+		return nil
+	}
+	if v.ClienteID == 0 {
+		return errors.New("identifique o cliente para resgatar pontos")
+	}
+	if pontos < 0 || !valor.Positivo() {
+		return errors.New("a quantidade de pontos deve ser maior que zero")
+	}
+	if disponivel := v.Subtotal().Subtrair(v.ValorDesconto()); valor.Comparar(disponivel) > 0 {
+		return fmt.Errorf("o resgate de %s excede o total da venda (%s)", valor, disponivel)
+	}
+	v.PontosResgatados, v.ValorResgate = pontos, valor
+	return nil
+}
+
+// GetStatus retorna o status da Venda.
+// Vendas gravadas antes da existência do status são consideradas finalizadas.
+func (v *Venda) GetStatus() StatusVenda {
+	if v.Status == "" {
+		return VendaFinalizada
+	}
+	return v.Status
+}
+
+// Aberta informa se a Venda ainda está em andamento e, portanto, pode ter os itens alterados.
+func (v *Venda) Aberta() bool {
+	return v.GetStatus() == VendaAberta
+}
+
+// Contabilizada informa se a Venda entra nos totais de faturamento.
+func (v *Venda) Contabilizada() bool {
+	return v.GetStatus().Contabilizada()
+}
+
+// Finalizar conclui a Venda aberta. Os pagamentos precisam cobrir o total da venda;
+// caso contrário, retorna um erro equivalente a ErrPagamentoInsuficiente.
+func (v *Venda) Finalizar() error {
+	if !v.GetStatus().PodeMudarPara(VendaFinalizada) {
+		return &ErroTransicao{De: v.GetStatus(), Para: VendaFinalizada}
+	}
+	if restante := v.Restante(); restante.Positivo() {
+		return fmt.Errorf("%w: faltam %s de %s", ErrPagamentoInsuficiente, restante, v.Total())
+	}
+	if err := v.mudarStatus(VendaFinalizada); err != nil {
+		return err
+	}
+	v.FinalizadaEm = time.Now()
+	return nil
+}
+
+// Cancelar anula a Venda, aberta ou finalizada, registrando o motivo.
+func (v *Venda) Cancelar(motivo string) error {
+	return v.encerrar(VendaCancelada, motivo)
+}
+
+// Estornar desfaz a Venda finalizada, registrando o motivo.
+func (v *Venda) Estornar(motivo string) error {
+	return v.encerrar(VendaEstornada, motivo)
+}
+
+// encerrar cancela ou estorna a Venda, exigindo um motivo.
+func (v *Venda) encerrar(status StatusVenda, motivo string) error {
+	motivo = strings.TrimSpace(motivo)
+	if motivo == "" {
+		return errors.New("informe o motivo")
+	}
+	if err := v.mudarStatus(status); err != nil {
+		return err
+	}
+	v.CanceladaEm = time.Now()
+	v.MotivoCancelamento = motivo
+	return nil
+}
+
+// mudarStatus passa a Venda para o novo status, se o ciclo de vida permitir.
+func (v *Venda) mudarStatus(novo StatusVenda) error {
+	atual := v.GetStatus()
+	if !atual.PodeMudarPara(novo) {
+		return &ErroTransicao{De: atual, Para: novo}
+	}
+	v.Status = novo
+	return nil
+}
+
+// Validar verifica se a Venda tem ao menos um item e se todas as quantidades são positivas.
+func (v *Venda) Validar() error {
+	if len(v.Itens) == 0 {
+		return errors.New("a venda precisa ter ao menos um item")
+	}
+	for _, item := range v.Itens {
+		if item.Quantidade <= 0 {
+			return fmt.Errorf("quantidade inválida para %s: %d", item.Produto.GetNome(), item.Quantidade)
+		}
+		if err := item.Desconto.Validar(item.ValorPromocional()); err != nil {
+			return fmt.Errorf("%s: %w", item.Produto.GetNome(), err)
+		}
+	}
+	if err := v.Desconto.Validar(v.Subtotal()); err != nil {
+		return err
+	}
+	if v.PontosResgatados != 0 || !v.ValorResgate.Zerado() {
+		if v.ClienteID == 0 || v.PontosResgatados < 0 || !v.ValorResgate.Positivo() {
+			return errors.New("resgate de pontos inválido")
+		}
+		if v.ValorResgate.Comparar(v.Subtotal().Subtrair(v.ValorDesconto())) > 0 {
+			return errors.New("o resgate de pontos excede o total da venda")
+		}
+	}
+	return v.validarPagamentos()
+}
+
+// Clonar retorna uma cópia da Venda, incluindo a lista de itens.
+func (v *Venda) Clonar() *Venda {
+	copia := *v
+	copia.Itens = slices.Clone(v.Itens)
+	for i := range copia.Itens {
+		copia.Itens[i].Tributos = slices.Clone(copia.Itens[i].Tributos)
+		copia.Itens[i].Promocoes = slices.Clone(copia.Itens[i].Promocoes)
+	}
+	copia.Pagamentos = slices.Clone(v.Pagamentos)
+	return &copia
+}
+
 // String retorna uma representação textual da Venda.
 func (v *Venda) String() string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Venda[ID=%d, DataHora=%s]\n", v.ID, v.DataHora.Format("2006-01-02 15:04:05")))
+	sb.WriteString(fmt.Sprintf("Venda[ID=%d, DataHora=%s, Status=%s]\n", v.ID, v.DataHora.Format("2006-01-02 15:04:05"), v.GetStatus()))
+	if v.ClienteID != 0 {
+		sb.WriteString(fmt.Sprintf("Cliente: %s (ID=%d)\n", v.NomeCliente, v.ClienteID))
+	}
+	if v.MotivoCancelamento != "" {
+		sb.WriteString(fmt.Sprintf("%s em %s: %s\n", v.GetStatus(), v.CanceladaEm.Format("2006-01-02 15:04:05"), v.MotivoCancelamento))
+	}
 	sb.WriteString("Itens:\n")
 	for _, item := range v.Itens {
 		sb.WriteString(fmt.Sprintf("  %s\n", item.String()))
+		if len(item.Promocoes) > 0 {
+			sb.WriteString(fmt.Sprintf("  %15s %-20s %12s\n", "", "promoção "+strings.Join(item.Promocoes, " + "), item.DescontoPromocao.Multiplicar(-1)))
+		}
+		if !item.Desconto.Vazio() {
+			sb.WriteString(fmt.Sprintf("  %15s %-20s %12s\n", "", "desconto "+item.Desconto.String(), item.ValorDesconto().Multiplicar(-1)))
+		}
+	}
+	if descontos := v.DescontoTotal(); !descontos.Zerado() {
+		sb.WriteString(fmt.Sprintf("SUBTOTAL: %s\n", v.Subtotal()))
+		if !v.Desconto.Vazio() {
+			sb.WriteString(fmt.Sprintf("DESCONTO NA VENDA (%s): %s\n", v.Desconto, v.ValorDesconto().Multiplicar(-1)))
+		}
+		if v.PontosResgatados > 0 {
+			sb.WriteString(fmt.Sprintf("RESGATE DE PONTOS (%d pontos): %s\n", v.PontosResgatados, v.ValorResgate.Multiplicar(-1)))
+		}
+		sb.WriteString(fmt.Sprintf("TOTAL DE DESCONTOS: %s\n", descontos.Multiplicar(-1)))
+	}
+	sb.WriteString(fmt.Sprintf("TOTAL: %s\n", v.Total()))
+	if len(v.Pagamentos) > 0 {
+		sb.WriteString("Pagamentos:\n")
+		for _, p := range v.Pagamentos {
+			sb.WriteString(fmt.Sprintf("  %s\n", p))
+		}
+		if troco := v.Troco(); troco.Positivo() {
+			sb.WriteString(fmt.Sprintf("TROCO: %s\n", troco))
+		}
+	}
+	if tributos := v.Tributos(); len(tributos) > 0 {
+		// Informação exigida pela Lei 12.741/2012 (Lei da Transparência Fiscal).
+		partes := []string{}
+		for _, t := range tributos {
+			partes = append(partes, fmt.Sprintf("%s %s", t.Tributo, t.Valor))
+		}
+		sb.WriteString(fmt.Sprintf("Valor aproximado dos tributos: %s (%s)\n", v.TotalTributos(), strings.Join(partes, ", ")))
 	}
-	sb.WriteString(fmt.Sprintf("TOTAL: %.2f\n", v.Total()))
 	return sb.String()
 }
 
@@ -49,6 +267,17 @@ func (v *Venda) GetItens() []ItemVenda {
 	return v.Itens
 }
 
+// QuantidadeDoProduto retorna a quantidade total vendida de um produto, somando todos os itens.
+func (v *Venda) QuantidadeDoProduto(produtoID int64) int {
+	quantidade := 0
+	for _, item := range v.Itens {
+		if item.Produto.GetID() == produtoID {
+			quantidade += item.Quantidade
+		}
+	}
+	return quantidade
+}
+
 // AdicionarItem adiciona um novo item à Venda.
 func (v *Venda) AdicionarItem(produto Produto, quantidade int) {
 	v.Itens = append(v.Itens, ItemVenda{
@@ -77,11 +306,134 @@ func (v *Venda) RemoverItemPorNome(nomeProduto string) {
 	v.Itens = filtrados
 }
 
-// Total calcula o valor total da Venda.
-func (v *Venda) Total() float64 {
-	total := 0.0
+// AplicarDescontoItem define o desconto do item na posição informada.
+// Um Desconto vazio remove o desconto do item.
+func (v *Venda) AplicarDescontoItem(posicao int, desconto Desconto) error {
+	if posicao < 0 || posicao >= len(v.Itens) {
+		return fmt.Errorf("a venda não tem o item %d", posicao+1)
+	}
+	if err := desconto.Validar(v.Itens[posicao].ValorPromocional()); err != nil {
+		return err
+	}
+	v.Itens[posicao].Desconto = desconto
+	return nil
+}
+
+// AplicarDesconto define o desconto no total da Venda.
+// Um Desconto vazio remove o desconto da venda.
+func (v *Venda) AplicarDesconto(desconto Desconto) error {
+	if err := desconto.Validar(v.Subtotal()); err != nil {
+		return err
+	}
+	v.Desconto = desconto
+	return nil
+}
+
+// Bruto retorna a soma dos valores brutos dos itens, antes de qualquer desconto.
+func (v *Venda) Bruto() Dinheiro {
+	total := Reais(0)
+	for _, item := range v.Itens {
+		total = total.Somar(item.Bruto())
+	}
+	return total
+}
+
+// Subtotal retorna a soma dos itens já com os descontos dos itens, sobre a qual
+// é calculado o desconto da venda.
+func (v *Venda) Subtotal() Dinheiro {
+	total := Reais(0)
+	for _, item := range v.Itens {
+		total = total.Somar(item.Total())
+	}
+	return total
+}
+
+// ValorDesconto retorna o valor do desconto no total da Venda.
+func (v *Venda) ValorDesconto() Dinheiro {
+	return v.Desconto.Calcular(v.Subtotal())
+}
+
+// ValorPromocional retorna a soma dos itens com os descontos das promoções, antes dos
+// descontos concedidos pelo operador.
+func (v *Venda) ValorPromocional() Dinheiro {
+	total := Reais(0)
 	for _, item := range v.Itens {
-		total += item.Valor * float64(item.Quantidade)
+		total = total.Somar(item.ValorPromocional())
+	}
+	return total
+}
+
+// DescontoTotal retorna a soma de todos os descontos: promoções, itens, venda e resgate de pontos.
+func (v *Venda) DescontoTotal() Dinheiro {
+	return v.Bruto().Subtrair(v.Total())
+}
+
+// DescontoManual retorna a soma dos descontos concedidos pelo operador, nos itens e na venda,
+// sem os descontos das promoções e o resgate de pontos.
+func (v *Venda) DescontoManual() Dinheiro {
+	return v.ValorPromocional().Subtrair(v.Total()).Subtrair(v.ValorResgate)
+}
+
+// PercentualDesconto retorna os descontos concedidos pelo operador em relação ao valor promocional.
+func (v *Venda) PercentualDesconto() Percentual {
+	return ProporcaoPercentual(v.DescontoManual(), v.ValorPromocional())
+}
+
+// LiquidoDoItem retorna o valor efetivamente pago pelo item na posição informada:
+// o total do item menos a sua parte do desconto da venda e do resgate de pontos, rateados
+// proporcionalmente ao total de cada item.
+func (v *Venda) LiquidoDoItem(posicao int) Dinheiro {
+	item := v.Itens[posicao]
+	subtotal := v.Subtotal()
+	if !subtotal.Positivo() {
+		return item.Total()
+	}
+	rateio := v.ValorDesconto().Somar(v.ValorResgate).MultiplicarFracao(item.Total().Centavos, subtotal.Centavos, ArredondamentoMeioParaCima)
+	return item.Total().Subtrair(rateio)
+}
+
+// CalcularTributos calcula, pela regra informada, os tributos de cada item sobre o valor
+// efetivamente pago por ele (ver LiquidoDoItem). Os valores ficam gravados nos itens,
+// de modo que alterações posteriores nas alíquotas não mudam vendas já finalizadas.
+func (v *Venda) CalcularTributos(regra RegraTributaria) {
+	for i := range v.Itens {
+		v.Itens[i].Tributos = regra.Calcular(v.Itens[i], v.LiquidoDoItem(i))
+	}
+}
+
+// Tributos retorna o total de cada tributo da Venda.
+func (v *Venda) Tributos() []ValorTributo {
+	valores := []ValorTributo{}
+	for _, item := range v.Itens {
+		valores = append(valores, item.Tributos...)
+	}
+	return SomarTributos(valores)
+}
+
+// TotalTributos retorna o valor aproximado de todos os tributos da Venda.
+func (v *Venda) TotalTributos() Dinheiro {
+	total := Reais(0)
+	for _, t := range v.Tributos() {
+		total = total.Somar(t.Valor)
+	}
+	return total
+}
+
+// Total calcula o valor total da Venda: o subtotal menos o desconto da venda e o resgate de pontos.
+// Cada desconto é arredondado para centavos uma única vez, de modo que o total
+// é sempre igual ao valor bruto menos os descontos exibidos na nota fiscal.
+func (v *Venda) Total() Dinheiro {
+	return v.Subtotal().Subtrair(v.ValorDesconto()).Subtrair(v.ValorResgate)
+}
+
+// SomarVendas soma o total das vendas contabilizadas, ignorando as abertas, as canceladas
+// e as estornadas.
+func SomarVendas(vendas []*Venda) Dinheiro {
+	total := Reais(0)
+	for _, v := range vendas {
+		if v.Contabilizada() {
+			total = total.Somar(v.Total())
+		}
 	}
 	return total
 }
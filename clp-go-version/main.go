@@ -2,12 +2,19 @@ package main
 
 import (
 	"bufio"
+	"clp-go-version/cli"
 	"clp-go-version/ui"
 	"fmt"
 	"os"
 )
 
 func main() {
+	// Com argumentos, executa o subcomando informado (ver o pacote cli) e encerra
+	// com o código de saída correspondente.
+	if len(os.Args) > 1 {
+		os.Exit(cli.Executar(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	// Cria uma instância do scanner para leitura do input do usuário.
 	scanner := bufio.NewScanner(os.Stdin)
 
@@ -0,0 +1,380 @@
+package relatorios
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ClasseABC é a classe de um produto na curva ABC: os produtos da classe A respondem pela
+// maior parte do faturamento, e os da classe C, pela menor.
+type ClasseABC string
+
+const (
+	ClasseA ClasseABC = "A"
+	ClasseB ClasseABC = "B"
+	ClasseC ClasseABC = "C"
+)
+
+// OpcoesAnalise configura a análise do catálogo (ver Analisar).
+type OpcoesAnalise struct {
+	Periodo       Periodo              // Vendas consideradas na curva ABC e no giro dos produtos.
+	LimiteA       entidades.Percentual // Faturamento acumulado coberto pela classe A.
+	LimiteB       entidades.Percentual // Faturamento acumulado coberto pelas classes A e B.
+	DiasParado    int                  // Dias sem venda a partir dos quais o estoque é considerado parado.
+	DiasCobertura int                  // Dias de venda que o estoque deve cobrir na sugestão de compra.
+}
+
+// OpcoesAnalisePadrao retorna as opções usuais: os últimos 90 dias até agora, classe A até
+// 80% do faturamento, classe B até 95%, estoque parado após 30 dias e cobertura de 15 dias.
+func OpcoesAnalisePadrao(agora time.Time) OpcoesAnalise {
+	return OpcoesAnalise{
+		Periodo:       EntreDatas(agora.AddDate(0, 0, -89), agora),
+		LimiteA:       8000,
+		LimiteB:       9500,
+		DiasParado:    30,
+		DiasCobertura: 15,
+	}
+}
+
+// ItemABC é a posição de um produto na curva ABC.
+type ItemABC struct {
+	ProdutoID    int64
+	Nome         string
+	Unidades     int
+	Faturamento  entidades.Dinheiro
+	Participacao entidades.Percentual // Parte do faturamento total.
+	Acumulado    entidades.Percentual // Participação somada à dos produtos anteriores na curva.
+	Classe       ClasseABC
+}
+
+// ProdutoParado é um produto com estoque e sem vendas há mais dias que o limite.
+type ProdutoParado struct {
+	ProdutoID    int64
+	Nome         string
+	Estoque      int
+	ValorEstoque entidades.Dinheiro // Estoque pelo valor de venda atual.
+	UltimaVenda  time.Time          // Zero se o produto nunca foi vendido.
+	DiasSemVenda int                // -1 se o produto nunca foi vendido.
+}
+
+// SugestaoCompra é um produto cujo estoque não cobre os dias de venda desejados.
+type SugestaoCompra struct {
+	ProdutoID   int64
+	Nome        string
+	Classe      ClasseABC
+	Estoque     int
+	MediaDiaria float64 // Unidades vendidas por dia em que o produto esteve disponível no período.
+	Cobertura   float64 // Dias de venda cobertos pelo estoque atual.
+	Quantidade  int     // Unidades a comprar para cobrir os dias desejados.
+}
+
+// Analise reúne a curva ABC, o estoque parado e as sugestões de compra do catálogo.
+type Analise struct {
+	Opcoes    OpcoesAnalise
+	GeradaEm  time.Time
+	Curva     []ItemABC
+	Parados   []ProdutoParado
+	Sugestoes []SugestaoCompra
+}
+
+// Analisar analisa os produtos cadastrados com base no histórico de vendas contabilizadas.
+// A curva ABC e as sugestões de compra usam as vendas do período das opções; o estoque
+// parado considera a última venda de cada produto em todo o histórico.
+func Analisar(opcoes OpcoesAnalise) *Analise {
+	agora := time.Now()
+	produtos := data.GetInstance().Consultar().Listar()
+	historico := VendasDoPeriodo(Periodo{})
+	var vendas []*entidades.Venda
+	for _, v := range historico {
+		if opcoes.Periodo.Contem(momento(v)) {
+			vendas = append(vendas, v)
+		}
+	}
+
+	curva := CurvaABC(produtos, vendas, opcoes.LimiteA, opcoes.LimiteB)
+	dias := DiasDisponiveis(produtos, historico, data.GetEstoqueInstance().Movimentos(0), opcoes.Periodo, agora)
+	return &Analise{
+		Opcoes:    opcoes,
+		GeradaEm:  agora,
+		Curva:     curva,
+		Parados:   EstoqueParado(produtos, historico, opcoes.DiasParado, agora),
+		Sugestoes: SugerirCompras(produtos, curva, dias, opcoes.DiasCobertura),
+	}
+}
+
+// CurvaABC classifica os produtos pelo faturamento nas vendas informadas (ver PorProduto),
+// do maior ao menor. Os produtos são da classe A enquanto o faturamento acumulado antes
+// deles não atinge limiteA, e da classe B enquanto não atinge limiteB; os demais, inclusive
+// os produtos cadastrados sem vendas, são da classe C. Os produtos vendidos e depois
+// removidos do cadastro também entram na curva.
+func CurvaABC(produtos []*entidades.Produto, vendas []*entidades.Venda, limiteA, limiteB entidades.Percentual) []ItemABC {
+	totais := PorProduto(vendas)
+	vendidos := map[int64]bool{}
+	faturamento := entidades.Reais(0)
+	for _, t := range totais {
+		vendidos[t.ProdutoID] = true
+		faturamento = faturamento.Somar(t.Faturamento)
+	}
+	semVenda := []*entidades.Produto{}
+	for _, p := range produtos {
+		if !vendidos[p.GetID()] {
+			semVenda = append(semVenda, p)
+		}
+	}
+	slices.SortFunc(semVenda, func(a, b *entidades.Produto) int {
+		return strings.Compare(data.NormalizarTexto(a.GetNome()), data.NormalizarTexto(b.GetNome()))
+	})
+
+	curva := make([]ItemABC, 0, len(totais)+len(semVenda))
+	acumulado := entidades.Reais(0)
+	for _, t := range totais {
+		anterior := entidades.ProporcaoPercentual(acumulado, faturamento)
+		acumulado = acumulado.Somar(t.Faturamento)
+		classe := ClasseC
+		switch {
+		case !t.Faturamento.Positivo():
+		case anterior < limiteA:
+			classe = ClasseA
+		case anterior < limiteB:
+			classe = ClasseB
+		}
+		curva = append(curva, ItemABC{
+			ProdutoID:    t.ProdutoID,
+			Nome:         t.Nome,
+			Unidades:     t.Unidades,
+			Faturamento:  t.Faturamento,
+			Participacao: t.Participacao,
+			Acumulado:    entidades.ProporcaoPercentual(acumulado, faturamento),
+			Classe:       classe,
+		})
+	}
+	for _, p := range semVenda {
+		curva = append(curva, ItemABC{
+			ProdutoID:   p.GetID(),
+			Nome:        p.GetNome(),
+			Faturamento: entidades.Reais(0),
+			Acumulado:   entidades.ProporcaoPercentual(acumulado, faturamento),
+			Classe:      ClasseC,
+		})
+	}
+	return curva
+}
+
+// EstoqueParado lista os produtos com estoque que não são vendidos há mais de dias dias,
+// ou que nunca foram vendidos, do maior valor em estoque ao menor.
+func EstoqueParado(produtos []*entidades.Produto, vendas []*entidades.Venda, dias int, agora time.Time) []ProdutoParado {
+	ultimas := map[int64]time.Time{}
+	for _, v := range contabilizadas(vendas) {
+		for _, item := range v.GetItens() {
+			if id := item.Produto.GetID(); momento(v).After(ultimas[id]) {
+				ultimas[id] = momento(v)
+			}
+		}
+	}
+
+	limite := inicioDoDia(agora).AddDate(0, 0, -dias)
+	parados := []ProdutoParado{}
+	for _, p := range produtos {
+		ultima := ultimas[p.GetID()]
+		if p.GetEstoque() <= 0 || !ultima.Before(limite) {
+			continue
+		}
+		parado := ProdutoParado{
+			ProdutoID:    p.GetID(),
+			Nome:         p.GetNome(),
+			Estoque:      p.GetEstoque(),
+			ValorEstoque: p.GetValor().Multiplicar(int64(p.GetEstoque())),
+			UltimaVenda:  ultima,
+			DiasSemVenda: -1,
+		}
+		if !ultima.IsZero() {
+			parado.DiasSemVenda = int(inicioDoDia(agora).Sub(inicioDoDia(ultima)).Round(24*time.Hour) / (24 * time.Hour))
+		}
+		parados = append(parados, parado)
+	}
+	slices.SortFunc(parados, func(a, b ProdutoParado) int {
+		if c := b.ValorEstoque.Comparar(a.ValorEstoque); c != 0 {
+			return c
+		}
+		return strings.Compare(data.NormalizarTexto(a.Nome), data.NormalizarTexto(b.Nome))
+	})
+	return parados
+}
+
+// SugerirCompras lista os produtos cadastrados vendidos no período cujo estoque não cobre
+// diasCobertura dias de venda, pela média diária das unidades vendidas nos dias em que cada
+// produto esteve disponível (ver DiasDisponiveis). A quantidade sugerida completa essa
+// cobertura. A lista segue a classe ABC e, dentro da classe, os produtos com menor
+// cobertura primeiro.
+func SugerirCompras(produtos []*entidades.Produto, curva []ItemABC, dias map[int64]int, diasCobertura int) []SugestaoCompra {
+	posicoes := map[int64]ItemABC{}
+	for _, item := range curva {
+		posicoes[item.ProdutoID] = item
+	}
+
+	sugestoes := []SugestaoCompra{}
+	for _, p := range produtos {
+		item := posicoes[p.GetID()]
+		if item.Unidades <= 0 || dias[p.GetID()] <= 0 {
+			continue
+		}
+		media := float64(item.Unidades) / float64(dias[p.GetID()])
+		necessario := int(math.Ceil(media * float64(diasCobertura)))
+		estoque := max(p.GetEstoque(), 0)
+		if estoque >= necessario {
+			continue
+		}
+		sugestoes = append(sugestoes, SugestaoCompra{
+			ProdutoID:   p.GetID(),
+			Nome:        p.GetNome(),
+			Classe:      item.Classe,
+			Estoque:     p.GetEstoque(),
+			MediaDiaria: media,
+			Cobertura:   float64(estoque) / media,
+			Quantidade:  necessario - estoque,
+		})
+	}
+	slices.SortFunc(sugestoes, func(a, b SugestaoCompra) int {
+		if c := strings.Compare(string(a.Classe), string(b.Classe)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Cobertura, b.Cobertura); c != 0 {
+			return c
+		}
+		return strings.Compare(data.NormalizarTexto(a.Nome), data.NormalizarTexto(b.Nome))
+	})
+	return sugestoes
+}
+
+// Tabelas retorna a análise como tabelas: o resumo por classe, a curva ABC, o estoque
+// parado e as sugestões de compra.
+func (a *Analise) Tabelas() []*Tabela {
+	return []*Tabela{
+		TabelaClasses(a.Opcoes.Periodo, a.Curva),
+		TabelaABC(a.Opcoes.Periodo, a.Curva),
+		TabelaParados(a.Opcoes.DiasParado, a.Parados),
+		TabelaSugestoes(a.Opcoes.DiasCobertura, a.Sugestoes),
+	}
+}
+
+// TabelaClasses resume a curva ABC com a quantidade de produtos e o faturamento de cada classe.
+func TabelaClasses(periodo Periodo, curva []ItemABC) *Tabela {
+	t := &Tabela{
+		Titulo:  "RESUMO DA CURVA ABC DE " + periodo.String(),
+		Colunas: []string{"CLASSE", "PRODUTOS", "FATURAMENTO", "PARTICIPAÇÃO"},
+	}
+	total := entidades.Reais(0)
+	for _, item := range curva {
+		total = total.Somar(item.Faturamento)
+	}
+	for _, classe := range []ClasseABC{ClasseA, ClasseB, ClasseC} {
+		produtos, faturamento := 0, entidades.Reais(0)
+		for _, item := range curva {
+			if item.Classe == classe {
+				produtos++
+				faturamento = faturamento.Somar(item.Faturamento)
+			}
+		}
+		t.Linhas = append(t.Linhas, []any{classe, produtos, faturamento, entidades.ProporcaoPercentual(faturamento, total)})
+	}
+	t.Rodape = []any{"TOTAL", len(curva), total, entidades.ProporcaoPercentual(total, total)}
+	return t
+}
+
+// TabelaABC retorna a curva ABC como tabela.
+func TabelaABC(periodo Periodo, curva []ItemABC) *Tabela {
+	t := &Tabela{
+		Titulo:  "CURVA ABC DE " + periodo.String(),
+		Colunas: []string{"PRODUTO", "CLASSE", "UNIDADES", "FATURAMENTO", "PARTICIPAÇÃO", "ACUMULADO"},
+	}
+	for _, item := range curva {
+		t.Linhas = append(t.Linhas, []any{item.Nome, item.Classe, item.Unidades, item.Faturamento, item.Participacao, item.Acumulado})
+	}
+	return t
+}
+
+// TabelaParados retorna o estoque parado como tabela, com o valor total em estoque.
+func TabelaParados(dias int, parados []ProdutoParado) *Tabela {
+	t := &Tabela{
+		Titulo:  fmt.Sprintf("ESTOQUE PARADO HÁ MAIS DE %d DIAS", dias),
+		Colunas: []string{"PRODUTO", "ESTOQUE", "VALOR EM ESTOQUE", "ÚLTIMA VENDA", "DIAS SEM VENDA"},
+	}
+	unidades, valor := 0, entidades.Reais(0)
+	for _, p := range parados {
+		var ultima, dias any = "nunca", "-"
+		if !p.UltimaVenda.IsZero() {
+			ultima, dias = inicioDoDia(p.UltimaVenda), p.DiasSemVenda
+		}
+		t.Linhas = append(t.Linhas, []any{p.Nome, p.Estoque, p.ValorEstoque, ultima, dias})
+		unidades += p.Estoque
+		valor = valor.Somar(p.ValorEstoque)
+	}
+	t.Rodape = []any{"TOTAL", unidades, valor, "", ""}
+	return t
+}
+
+// TabelaSugestoes retorna as sugestões de compra como tabela.
+func TabelaSugestoes(diasCobertura int, sugestoes []SugestaoCompra) *Tabela {
+	t := &Tabela{
+		Titulo:  fmt.Sprintf("SUGESTÃO DE COMPRA PARA %d DIAS DE VENDA", diasCobertura),
+		Colunas: []string{"PRODUTO", "CLASSE", "ESTOQUE", "VENDA/DIA", "COBERTURA (DIAS)", "COMPRAR"},
+	}
+	for _, s := range sugestoes {
+		t.Linhas = append(t.Linhas, []any{s.Nome, s.Classe, s.Estoque, s.MediaDiaria, s.Cobertura, s.Quantidade})
+	}
+	return t
+}
+
+// DiasDisponiveis retorna, para cada produto, os dias do período até hoje usados no cálculo
+// da sua média diária de vendas. A contagem começa no início do período ou, se for
+// posterior, no dia da primeira entrada de estoque ou da primeira venda do produto, o que
+// vier primeiro, para que um produto cadastrado no meio do período não tenha a média
+// diluída pelos dias em que ainda não existia. Sem início do período, os dias são zero.
+func DiasDisponiveis(produtos []*entidades.Produto, vendas []*entidades.Venda, movimentos []*entidades.MovimentoEstoque, p Periodo, agora time.Time) map[int64]int {
+	primeiros := map[int64]time.Time{}
+	registrar := func(produtoID int64, instante time.Time) {
+		if atual, ok := primeiros[produtoID]; !ok || instante.Before(atual) {
+			primeiros[produtoID] = instante
+		}
+	}
+	for _, m := range movimentos {
+		if m.Quantidade > 0 {
+			registrar(m.ProdutoID, m.DataHora)
+		}
+	}
+	for _, v := range contabilizadas(vendas) {
+		for _, item := range v.GetItens() {
+			registrar(item.Produto.GetID(), momento(v))
+		}
+	}
+
+	dias := map[int64]int{}
+	for _, produto := range produtos {
+		disponivel := p
+		if primeiro, ok := primeiros[produto.GetID()]; ok && !p.Inicio.IsZero() && primeiro.After(p.Inicio) {
+			disponivel.Inicio = inicioDoDia(primeiro)
+		}
+		dias[produto.GetID()] = diasDoPeriodo(disponivel, agora)
+	}
+	return dias
+}
+
+// diasDoPeriodo retorna a quantidade de dias do período até hoje, usada no cálculo da
+// média diária de vendas. Para um período sem início, retorna zero, e nenhuma compra é
+// sugerida.
+func diasDoPeriodo(p Periodo, agora time.Time) int {
+	if p.Inicio.IsZero() {
+		return 0
+	}
+	fim := p.Fim
+	if fim.IsZero() || fim.After(agora) {
+		fim = inicioDoDia(agora).AddDate(0, 0, 1)
+	}
+	return max(int(fim.Sub(p.Inicio).Round(24*time.Hour)/(24*time.Hour)), 1)
+}
@@ -0,0 +1,32 @@
+package relatorios
+
+import (
+	"clp-go-version/entidades"
+	"testing"
+	"time"
+)
+
+func TestDiasDisponiveisComecamNaPrimeiraEntrada(t *testing.T) {
+	agora := time.Date(2024, 3, 30, 15, 0, 0, 0, time.Local)
+	antigo := entidades.NewProduto("Caderno", entidades.Reais(1500))
+	antigo.SetID(1)
+	novo := entidades.NewProduto("Agenda", entidades.Reais(3000))
+	novo.SetID(2)
+	movimentos := []*entidades.MovimentoEstoque{
+		{ProdutoID: 1, Tipo: entidades.MovimentoEntrada, Quantidade: 50, DataHora: agora.AddDate(0, -2, 0)},
+		{ProdutoID: 2, Tipo: entidades.MovimentoEntrada, Quantidade: 20, DataHora: agora.AddDate(0, 0, -4)},
+	}
+
+	periodo := Periodo{Inicio: inicioDoDia(agora).AddDate(0, 0, -29)}
+	dias := DiasDisponiveis([]*entidades.Produto{antigo, novo}, nil, movimentos, periodo, agora)
+	if dias[1] != 30 || dias[2] != 5 {
+		t.Errorf("dias = %v, esperado 30 para o produto 1 e 5 para o produto 2", dias)
+	}
+
+	curva := []ItemABC{{ProdutoID: 2, Unidades: 10}}
+	novo.SetEstoque(4)
+	sugestoes := SugerirCompras([]*entidades.Produto{novo}, curva, dias, 7)
+	if len(sugestoes) != 1 || sugestoes[0].Quantidade != 10 {
+		t.Errorf("sugestões = %+v, esperado comprar 10 unidades (2 por dia durante 7 dias, menos 4 em estoque)", sugestoes)
+	}
+}
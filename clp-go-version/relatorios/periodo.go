@@ -0,0 +1,95 @@
+// Package relatorios resume as vendas registradas no DAO de vendas: totais diários, ticket
+// médio, unidades e faturamento por produto, vendas por hora e a comparação entre períodos.
+//
+// Os relatórios consideram apenas as vendas contabilizadas (ver StatusVenda.Contabilizada),
+// datadas pela finalização, e usam o total de cada venda (Venda.Total), já descontados os
+// descontos e o resgate de pontos. As devoluções parciais são deduzidas das unidades e do
+// faturamento das vendas a que pertencem (ver Resumo).
+package relatorios
+
+import (
+	"fmt"
+	"time"
+)
+
+// formatoData é o formato das datas exibidas nos relatórios.
+const formatoData = "02/01/2006"
+
+// Periodo é o intervalo de tempo de um relatório, de Inicio (inclusive) a Fim (exclusive).
+// Um limite zerado deixa o período aberto daquele lado.
+type Periodo struct {
+	Inicio time.Time
+	Fim    time.Time
+}
+
+// EntreDatas cria o período que vai do início do dia inicial ao fim do dia final.
+// Uma data zerada deixa o período aberto daquele lado.
+func EntreDatas(inicial, final time.Time) Periodo {
+	var p Periodo
+	if !inicial.IsZero() {
+		p.Inicio = inicioDoDia(inicial)
+	}
+	if !final.IsZero() {
+		p.Fim = inicioDoDia(final).AddDate(0, 0, 1)
+	}
+	return p
+}
+
+// Dia cria o período do dia de t.
+func Dia(t time.Time) Periodo {
+	return EntreDatas(t, t)
+}
+
+// Mes cria o período do mês de t.
+func Mes(t time.Time) Periodo {
+	inicio := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Periodo{Inicio: inicio, Fim: inicio.AddDate(0, 1, 0)}
+}
+
+// Contem informa se o instante t está dentro do período.
+func (p Periodo) Contem(t time.Time) bool {
+	return (p.Inicio.IsZero() || !t.Before(p.Inicio)) && (p.Fim.IsZero() || t.Before(p.Fim))
+}
+
+// Limitado informa se o período tem início e fim.
+func (p Periodo) Limitado() bool {
+	return !p.Inicio.IsZero() && !p.Fim.IsZero()
+}
+
+// Anterior retorna o período de mesma duração imediatamente anterior a p, usado nas
+// comparações. Um mês completo é comparado com o mês anterior inteiro. Para um período
+// sem início ou sem fim, retorna o próprio período.
+func (p Periodo) Anterior() Periodo {
+	if !p.Limitado() {
+		return p
+	}
+	if p == Mes(p.Inicio) {
+		return Mes(p.Inicio.AddDate(0, -1, 0))
+	}
+	dias := int(p.Fim.Sub(p.Inicio).Round(24*time.Hour) / (24 * time.Hour))
+	if dias > 0 && p.Inicio.AddDate(0, 0, dias).Equal(p.Fim) {
+		return Periodo{Inicio: p.Inicio.AddDate(0, 0, -dias), Fim: p.Inicio}
+	}
+	return Periodo{Inicio: p.Inicio.Add(-p.Fim.Sub(p.Inicio)), Fim: p.Inicio}
+}
+
+// String retorna o período com as datas do primeiro e do último dia, como
+// "01/10/2026 a 31/10/2026".
+func (p Periodo) String() string {
+	inicio, fim := "o início", "hoje"
+	if !p.Inicio.IsZero() {
+		inicio = p.Inicio.Format(formatoData)
+	}
+	if !p.Fim.IsZero() {
+		fim = p.Fim.Add(-time.Nanosecond).Format(formatoData)
+	}
+	if inicio == fim {
+		return inicio
+	}
+	return fmt.Sprintf("%s a %s", inicio, fim)
+}
+
+// inicioDoDia retorna a meia-noite do dia de t.
+func inicioDoDia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
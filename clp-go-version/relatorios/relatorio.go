@@ -0,0 +1,55 @@
+package relatorios
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Relatorio reúne todos os relatórios de vendas de um período, para exportação.
+type Relatorio struct {
+	GeradoEm   time.Time
+	Resumo     Resumo
+	Comparacao *Comparacao `json:",omitempty"` // Comparação com o período anterior; nil se o período não for limitado.
+	Diario     []TotalDiario
+	Produtos   []TotalProduto
+	Horas      []TotalHora
+}
+
+// Gerar calcula os relatórios das vendas do período e, se ele tiver início e fim, a
+// comparação com o período anterior de mesma duração (ver Periodo.Anterior).
+func Gerar(periodo Periodo) *Relatorio {
+	vendas := VendasDoPeriodo(periodo)
+	r := &Relatorio{
+		GeradoEm: time.Now(),
+		Resumo:   Resumir(periodo, vendas),
+		Diario:   TotaisDiarios(vendas),
+		Produtos: PorProduto(vendas),
+		Horas:    PorHora(vendas),
+	}
+	if periodo.Limitado() {
+		comparacao := Comparar(periodo, periodo.Anterior())
+		r.Comparacao = &comparacao
+	}
+	return r
+}
+
+// Tabelas retorna os relatórios como tabelas, na ordem em que são exibidos.
+func (r *Relatorio) Tabelas() []*Tabela {
+	periodo := r.Resumo.Periodo
+	tabelas := []*Tabela{r.Resumo.Tabela()}
+	if r.Comparacao != nil {
+		tabelas = append(tabelas, r.Comparacao.Tabela())
+	}
+	return append(tabelas,
+		TabelaDiaria(periodo, r.Diario),
+		TabelaProdutos(periodo, r.Produtos),
+		TabelaHoras(periodo, r.Horas))
+}
+
+// EscreverJSON grava o relatório em formato JSON indentado.
+func (r *Relatorio) EscreverJSON(w io.Writer) error {
+	codificador := json.NewEncoder(w)
+	codificador.SetIndent("", "  ")
+	return codificador.Encode(r)
+}
@@ -0,0 +1,148 @@
+package relatorios
+
+import (
+	"clp-go-version/entidades"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Tabela é o resultado de um relatório em linhas e colunas, exibido no terminal por String
+// e exportado por EscreverCSV. As células guardam os valores (Dinheiro, Percentual, datas,
+// números e textos), formatados de acordo com o destino.
+type Tabela struct {
+	Titulo  string
+	Colunas []string
+	Linhas  [][]any
+	Rodape  []any // Linha de totais, exibida após as demais; nil se não houver.
+}
+
+// String formata a tabela para o terminal, com as colunas alinhadas: a primeira à esquerda
+// e as demais, geralmente valores, à direita.
+func (t *Tabela) String() string {
+	linhas := [][]string{t.Colunas}
+	for _, linha := range t.linhas() {
+		textos := make([]string, len(linha))
+		for i, valor := range linha {
+			textos[i] = formatarCelula(valor)
+		}
+		linhas = append(linhas, textos)
+	}
+
+	larguras := make([]int, len(t.Colunas))
+	for _, linha := range linhas {
+		for i, texto := range linha {
+			larguras[i] = max(larguras[i], utf8.RuneCountInString(texto))
+		}
+	}
+
+	var sb strings.Builder
+	if t.Titulo != "" {
+		sb.WriteString(t.Titulo + "\n")
+	}
+	for n, linha := range linhas {
+		if n == len(linhas)-1 && t.Rodape != nil {
+			sb.WriteString(separador(larguras))
+		}
+		var celulas strings.Builder
+		for i, texto := range linha {
+			espacos := strings.Repeat(" ", larguras[i]-utf8.RuneCountInString(texto))
+			if i > 0 {
+				celulas.WriteString("  " + espacos + texto)
+			} else {
+				celulas.WriteString(texto + espacos)
+			}
+		}
+		sb.WriteString(strings.TrimRight(celulas.String(), " ") + "\n")
+		if n == 0 {
+			sb.WriteString(separador(larguras))
+		}
+	}
+	return sb.String()
+}
+
+// EscreverCSV grava a tabela em formato CSV, com os valores sem símbolo de moeda nem
+// separador de milhar. Com o separador ';', usado pelas planilhas em português, os decimais
+// são gravados com vírgula. Com separador zero, usa ','.
+func (t *Tabela) EscreverCSV(w io.Writer, separador rune) error {
+	escritor := csv.NewWriter(w)
+	if separador != 0 {
+		escritor.Comma = separador
+	}
+	if err := escritor.Write(t.Colunas); err != nil {
+		return err
+	}
+	for _, linha := range t.linhas() {
+		registro := make([]string, len(linha))
+		for i, valor := range linha {
+			registro[i] = celulaCSV(valor, escritor.Comma == ';')
+		}
+		if err := escritor.Write(registro); err != nil {
+			return err
+		}
+	}
+	escritor.Flush()
+	return escritor.Error()
+}
+
+// linhas retorna as linhas da tabela seguidas do rodapé, se houver.
+func (t *Tabela) linhas() [][]any {
+	if t.Rodape == nil {
+		return t.Linhas
+	}
+	return append(t.Linhas[:len(t.Linhas):len(t.Linhas)], t.Rodape)
+}
+
+// separador retorna a linha que separa o cabeçalho e o rodapé das demais linhas.
+func separador(larguras []int) string {
+	partes := make([]string, len(larguras))
+	for i, largura := range larguras {
+		partes[i] = strings.Repeat("-", largura)
+	}
+	return strings.Join(partes, "  ") + "\n"
+}
+
+// formatarCelula formata um valor para exibição no terminal.
+func formatarCelula(valor any) string {
+	switch v := valor.(type) {
+	case time.Time:
+		return v.Format(formatoData)
+	case float64:
+		return strings.Replace(strconv.FormatFloat(v, 'f', 2, 64), ".", ",", 1)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// celulaCSV formata um valor para o CSV. Com virgula, os decimais usam vírgula.
+func celulaCSV(valor any, virgula bool) string {
+	var texto string
+	switch v := valor.(type) {
+	case entidades.Dinheiro:
+		texto = v.Decimal()
+	case entidades.Percentual:
+		sinal, p := "", int64(v)
+		if p < 0 {
+			sinal, p = "-", -p
+		}
+		texto = fmt.Sprintf("%s%d.%02d", sinal, p/100, p%100)
+	case float64:
+		texto = strconv.FormatFloat(v, 'f', 2, 64)
+	case time.Time:
+		return v.Format("2006-01-02")
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return fmt.Sprint(v)
+	}
+	if virgula {
+		texto = strings.Replace(texto, ".", ",", 1)
+	}
+	return texto
+}
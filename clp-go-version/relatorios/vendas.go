@@ -0,0 +1,348 @@
+package relatorios
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Resumo totaliza as vendas de um período.
+//
+// As devoluções parciais (ver entidades.Devolucao) são descontadas das vendas a que
+// pertencem: as unidades e o faturamento deste e dos demais relatórios de vendas são
+// líquidos das devoluções, e o valor devolvido é informado à parte em Devolucoes.
+type Resumo struct {
+	Periodo     Periodo
+	Vendas      int                // Quantidade de vendas contabilizadas.
+	Unidades    int                // Unidades vendidas e não devolvidas, somando todos os itens.
+	Faturamento entidades.Dinheiro // Soma dos totais das vendas, menos as devoluções.
+	Devolucoes  entidades.Dinheiro // Valor creditado pelas devoluções das vendas.
+	Descontos   entidades.Dinheiro // Descontos, promoções e resgates de pontos concedidos.
+	TicketMedio entidades.Dinheiro // Faturamento dividido pela quantidade de vendas.
+}
+
+// TotalDiario totaliza as vendas de um dia.
+type TotalDiario struct {
+	Data        time.Time
+	Vendas      int
+	Unidades    int
+	Faturamento entidades.Dinheiro
+	TicketMedio entidades.Dinheiro
+}
+
+// TotalProduto totaliza as vendas de um produto.
+type TotalProduto struct {
+	ProdutoID    int64
+	Nome         string               // Nome do produto na venda mais recente.
+	Unidades     int                  // Unidades vendidas, menos as devolvidas.
+	Faturamento  entidades.Dinheiro   // Valor pago pelos itens, após os descontos (ver Venda.LiquidoDoItem) e as devoluções.
+	Participacao entidades.Percentual // Parte do faturamento de todos os produtos.
+}
+
+// TotalHora totaliza as vendas finalizadas em uma hora do dia, somando todos os dias.
+type TotalHora struct {
+	Hora        int // De 0 a 23.
+	Vendas      int
+	Faturamento entidades.Dinheiro
+}
+
+// Comparacao compara os resumos de dois períodos. As variações são relativas ao período
+// anterior e ficam zeradas quando ele não teve vendas.
+type Comparacao struct {
+	Atual               Resumo
+	Anterior            Resumo
+	VariacaoVendas      entidades.Percentual
+	VariacaoFaturamento entidades.Percentual
+	VariacaoTicket      entidades.Percentual
+}
+
+// VendasDoPeriodo retorna as vendas contabilizadas finalizadas no período, em ordem de
+// finalização.
+func VendasDoPeriodo(periodo Periodo) []*entidades.Venda {
+	return data.GetVendaInstance().Consultar().
+		Onde(func(v *entidades.Venda) bool { return v.Contabilizada() && periodo.Contem(momento(v)) }).
+		OrdenarPor(data.PorChave(func(v *entidades.Venda) int64 { return momento(v).UnixNano() })).
+		Listar()
+}
+
+// Resumir totaliza as vendas contabilizadas informadas, atribuídas ao período.
+func Resumir(periodo Periodo, vendas []*entidades.Venda) Resumo {
+	resumo := Resumo{Periodo: periodo, Faturamento: entidades.Reais(0), Devolucoes: entidades.Reais(0), Descontos: entidades.Reais(0)}
+	for _, v := range contabilizadas(vendas) {
+		liquido := liquidoDaVenda(v)
+		resumo.Vendas++
+		resumo.Unidades += liquido.unidades
+		resumo.Faturamento = resumo.Faturamento.Somar(liquido.total)
+		resumo.Devolucoes = resumo.Devolucoes.Somar(liquido.devolvido)
+		resumo.Descontos = resumo.Descontos.Somar(v.Bruto().Subtrair(v.Total()))
+	}
+	resumo.TicketMedio = ticketMedio(resumo.Faturamento, resumo.Vendas)
+	return resumo
+}
+
+// TotaisDiarios totaliza as vendas contabilizadas informadas por dia de finalização,
+// do dia mais antigo ao mais recente. Os dias sem vendas não são listados.
+func TotaisDiarios(vendas []*entidades.Venda) []TotalDiario {
+	porDia := map[time.Time]*TotalDiario{}
+	for _, v := range contabilizadas(vendas) {
+		dia := inicioDoDia(momento(v))
+		total, ok := porDia[dia]
+		if !ok {
+			total = &TotalDiario{Data: dia, Faturamento: entidades.Reais(0)}
+			porDia[dia] = total
+		}
+		liquido := liquidoDaVenda(v)
+		total.Vendas++
+		total.Unidades += liquido.unidades
+		total.Faturamento = total.Faturamento.Somar(liquido.total)
+	}
+
+	totais := make([]TotalDiario, 0, len(porDia))
+	for _, total := range porDia {
+		total.TicketMedio = ticketMedio(total.Faturamento, total.Vendas)
+		totais = append(totais, *total)
+	}
+	slices.SortFunc(totais, func(a, b TotalDiario) int { return a.Data.Compare(b.Data) })
+	return totais
+}
+
+// PorProduto totaliza as unidades e o faturamento de cada produto nas vendas contabilizadas
+// informadas, líquidos das devoluções, do produto de maior faturamento ao de menor.
+func PorProduto(vendas []*entidades.Venda) []TotalProduto {
+	porProduto := map[int64]*TotalProduto{}
+	total := entidades.Reais(0)
+	for _, v := range contabilizadas(vendas) {
+		itens := v.GetItens()
+		liquidos := make([]entidades.Dinheiro, len(itens))
+		quantidades := make([]int, len(itens))
+		for i, item := range itens {
+			liquidos[i], quantidades[i] = v.LiquidoDoItem(i), item.Quantidade
+		}
+		for _, devolucao := range data.GetDevolucaoInstance().PorVenda(v.GetID()) {
+			for _, item := range devolucao.GetItens() {
+				if item.Posicao >= 0 && item.Posicao < len(itens) {
+					liquidos[item.Posicao] = liquidos[item.Posicao].Subtrair(item.Total())
+					quantidades[item.Posicao] -= item.Quantidade
+				}
+			}
+		}
+
+		for i, item := range itens {
+			id := item.Produto.GetID()
+			produto, ok := porProduto[id]
+			if !ok {
+				produto = &TotalProduto{ProdutoID: id, Faturamento: entidades.Reais(0)}
+				porProduto[id] = produto
+			}
+			produto.Nome = item.Produto.GetNome()
+			produto.Unidades += quantidades[i]
+			produto.Faturamento = produto.Faturamento.Somar(liquidos[i])
+			total = total.Somar(liquidos[i])
+		}
+	}
+
+	produtos := make([]TotalProduto, 0, len(porProduto))
+	for _, p := range porProduto {
+		p.Participacao = entidades.ProporcaoPercentual(p.Faturamento, total)
+		produtos = append(produtos, *p)
+	}
+	slices.SortFunc(produtos, func(a, b TotalProduto) int {
+		if c := b.Faturamento.Comparar(a.Faturamento); c != 0 {
+			return c
+		}
+		return strings.Compare(data.NormalizarTexto(a.Nome), data.NormalizarTexto(b.Nome))
+	})
+	return produtos
+}
+
+// PorHora totaliza as vendas contabilizadas informadas pela hora da finalização, formando a
+// curva de vendas ao longo do dia. Retorna as 24 horas, inclusive as sem vendas.
+func PorHora(vendas []*entidades.Venda) []TotalHora {
+	horas := make([]TotalHora, 24)
+	for h := range horas {
+		horas[h] = TotalHora{Hora: h, Faturamento: entidades.Reais(0)}
+	}
+	for _, v := range contabilizadas(vendas) {
+		h := momento(v).Hour()
+		horas[h].Vendas++
+		horas[h].Faturamento = horas[h].Faturamento.Somar(liquidoDaVenda(v).total)
+	}
+	return horas
+}
+
+// Comparar resume as vendas do período atual e do anterior e calcula as variações.
+func Comparar(atual, anterior Periodo) Comparacao {
+	c := Comparacao{
+		Atual:    Resumir(atual, VendasDoPeriodo(atual)),
+		Anterior: Resumir(anterior, VendasDoPeriodo(anterior)),
+	}
+	c.VariacaoVendas = variacao(int64(c.Atual.Vendas), int64(c.Anterior.Vendas))
+	c.VariacaoFaturamento = variacao(c.Atual.Faturamento.Centavos, c.Anterior.Faturamento.Centavos)
+	c.VariacaoTicket = variacao(c.Atual.TicketMedio.Centavos, c.Anterior.TicketMedio.Centavos)
+	return c
+}
+
+// Tabela retorna o resumo como uma tabela de indicadores.
+func (r Resumo) Tabela() *Tabela {
+	return &Tabela{
+		Titulo:  "RESUMO DE " + r.Periodo.String(),
+		Colunas: []string{"INDICADOR", "VALOR"},
+		Linhas: [][]any{
+			{"Vendas", r.Vendas},
+			{"Unidades", r.Unidades},
+			{"Faturamento", r.Faturamento},
+			{"Devoluções", r.Devolucoes},
+			{"Descontos", r.Descontos},
+			{"Ticket médio", r.TicketMedio},
+		},
+	}
+}
+
+// TabelaDiaria retorna os totais diários como tabela, com uma linha de totais.
+func TabelaDiaria(periodo Periodo, totais []TotalDiario) *Tabela {
+	t := &Tabela{
+		Titulo:  "VENDAS POR DIA DE " + periodo.String(),
+		Colunas: []string{"DATA", "VENDAS", "UNIDADES", "FATURAMENTO", "TICKET MÉDIO"},
+	}
+	vendas, unidades, faturamento := 0, 0, entidades.Reais(0)
+	for _, d := range totais {
+		t.Linhas = append(t.Linhas, []any{d.Data, d.Vendas, d.Unidades, d.Faturamento, d.TicketMedio})
+		vendas += d.Vendas
+		unidades += d.Unidades
+		faturamento = faturamento.Somar(d.Faturamento)
+	}
+	t.Rodape = []any{"TOTAL", vendas, unidades, faturamento, ticketMedio(faturamento, vendas)}
+	return t
+}
+
+// TabelaProdutos retorna os totais por produto como tabela, com uma linha de totais.
+func TabelaProdutos(periodo Periodo, produtos []TotalProduto) *Tabela {
+	t := &Tabela{
+		Titulo:  "VENDAS POR PRODUTO DE " + periodo.String(),
+		Colunas: []string{"PRODUTO", "UNIDADES", "FATURAMENTO", "PARTICIPAÇÃO"},
+	}
+	unidades, faturamento := 0, entidades.Reais(0)
+	for _, p := range produtos {
+		t.Linhas = append(t.Linhas, []any{p.Nome, p.Unidades, p.Faturamento, p.Participacao})
+		unidades += p.Unidades
+		faturamento = faturamento.Somar(p.Faturamento)
+	}
+	t.Rodape = []any{"TOTAL", unidades, faturamento, entidades.CemPorCento}
+	return t
+}
+
+// TabelaHoras retorna a curva de vendas por hora como tabela, com uma barra proporcional
+// ao faturamento de cada hora. As horas sem vendas antes da primeira e após a última
+// venda são omitidas.
+func TabelaHoras(periodo Periodo, horas []TotalHora) *Tabela {
+	t := &Tabela{
+		Titulo:  "VENDAS POR HORA DE " + periodo.String(),
+		Colunas: []string{"HORA", "VENDAS", "FATURAMENTO", "CURVA"},
+	}
+	primeira := slices.IndexFunc(horas, func(h TotalHora) bool { return h.Vendas > 0 })
+	if primeira < 0 {
+		return t
+	}
+	ultima := len(horas) - 1
+	for horas[ultima].Vendas == 0 {
+		ultima--
+	}
+	maior := entidades.Reais(0)
+	for _, h := range horas {
+		if h.Faturamento.Comparar(maior) > 0 {
+			maior = h.Faturamento
+		}
+	}
+	for _, h := range horas[primeira : ultima+1] {
+		barra := ""
+		if maior.Positivo() {
+			barra = strings.Repeat("#", int(math.Round(float64(h.Faturamento.Centavos)*larguraCurva/float64(maior.Centavos))))
+		}
+		t.Linhas = append(t.Linhas, []any{fmt.Sprintf("%02d:00", h.Hora), h.Vendas, h.Faturamento, barra})
+	}
+	return t
+}
+
+// larguraCurva é a largura, em caracteres, da barra da hora de maior faturamento.
+const larguraCurva = 30
+
+// Tabela retorna a comparação como tabela, com uma linha por indicador.
+func (c Comparacao) Tabela() *Tabela {
+	return &Tabela{
+		Titulo:  fmt.Sprintf("COMPARAÇÃO ENTRE %s E %s", c.Atual.Periodo, c.Anterior.Periodo),
+		Colunas: []string{"INDICADOR", "ATUAL", "ANTERIOR", "VARIAÇÃO"},
+		Linhas: [][]any{
+			{"Vendas", c.Atual.Vendas, c.Anterior.Vendas, c.VariacaoVendas},
+			{"Unidades", c.Atual.Unidades, c.Anterior.Unidades, variacao(int64(c.Atual.Unidades), int64(c.Anterior.Unidades))},
+			{"Faturamento", c.Atual.Faturamento, c.Anterior.Faturamento, c.VariacaoFaturamento},
+			{"Devoluções", c.Atual.Devolucoes, c.Anterior.Devolucoes, variacao(c.Atual.Devolucoes.Centavos, c.Anterior.Devolucoes.Centavos)},
+			{"Descontos", c.Atual.Descontos, c.Anterior.Descontos, variacao(c.Atual.Descontos.Centavos, c.Anterior.Descontos.Centavos)},
+			{"Ticket médio", c.Atual.TicketMedio, c.Anterior.TicketMedio, c.VariacaoTicket},
+		},
+	}
+}
+
+// momento retorna o instante em que a venda passou a contar no faturamento: a finalização
+// ou, em vendas antigas sem essa data, a abertura.
+func momento(v *entidades.Venda) time.Time {
+	if v.FinalizadaEm.IsZero() {
+		return v.GetDataHora()
+	}
+	return v.FinalizadaEm
+}
+
+// contabilizadas filtra as vendas que entram no faturamento.
+func contabilizadas(vendas []*entidades.Venda) []*entidades.Venda {
+	var filtradas []*entidades.Venda
+	for _, v := range vendas {
+		if v.Contabilizada() {
+			filtradas = append(filtradas, v)
+		}
+	}
+	return filtradas
+}
+
+// liquido é o resultado de uma venda descontadas as suas devoluções.
+type liquido struct {
+	unidades  int                // Unidades vendidas menos as devolvidas.
+	total     entidades.Dinheiro // Total da venda menos o valor devolvido.
+	devolvido entidades.Dinheiro
+}
+
+// liquidoDaVenda calcula as unidades e o total da venda descontando as devoluções
+// registradas para ela.
+func liquidoDaVenda(v *entidades.Venda) liquido {
+	l := liquido{total: v.Total(), devolvido: entidades.Reais(0)}
+	for _, item := range v.GetItens() {
+		l.unidades += item.Quantidade
+	}
+	for _, devolucao := range data.GetDevolucaoInstance().PorVenda(v.GetID()) {
+		for _, item := range devolucao.GetItens() {
+			l.unidades -= item.Quantidade
+		}
+		l.devolvido = l.devolvido.Somar(devolucao.Total())
+	}
+	l.total = l.total.Subtrair(l.devolvido)
+	return l
+}
+
+// ticketMedio divide o faturamento pela quantidade de vendas, arredondando para centavos.
+func ticketMedio(faturamento entidades.Dinheiro, vendas int) entidades.Dinheiro {
+	if vendas == 0 {
+		return entidades.Reais(0)
+	}
+	return faturamento.MultiplicarFracao(1, int64(vendas), entidades.ArredondamentoMeioParaCima)
+}
+
+// variacao calcula a variação percentual de atual em relação a anterior, zerada quando
+// anterior é zero.
+func variacao(atual, anterior int64) entidades.Percentual {
+	if anterior == 0 {
+		return 0
+	}
+	return entidades.Percentual(math.Round(float64(atual-anterior) * float64(entidades.CemPorCento) / float64(anterior)))
+}
@@ -0,0 +1,72 @@
+package relatorios
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"os"
+	"testing"
+)
+
+// TestMain grava os dados dos DAOs em um diretório temporário.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "relatorios")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("CLP_DADOS", dir)
+	codigo := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(codigo)
+}
+
+func TestRelatoriosDescontamDevolucoes(t *testing.T) {
+	produto := entidades.NewProduto("Caneca", entidades.Reais(2500))
+	if err := data.GetInstance().Adicionar(produto); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := data.GetEstoqueInstance().Entrada(produto.GetID(), 10, "estoque inicial"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := data.GetCaixaInstance().Abrir("teste", entidades.Reais(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	vendas := data.GetVendaInstance()
+	venda := entidades.NewVenda()
+	venda.AdicionarItem(*produto, 4)
+	if err := vendas.Adicionar(venda); err != nil {
+		t.Fatal(err)
+	}
+	venda, err := vendas.Finalizar(venda.GetID(), []entidades.Pagamento{entidades.NewPagamento(entidades.PagamentoDinheiro, entidades.Reais(10000))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	devolucao := entidades.NewDevolucao(venda, "com defeito")
+	if err := devolucao.AdicionarItem(venda, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.GetDevolucaoInstance().Adicionar(devolucao); err != nil {
+		t.Fatal(err)
+	}
+
+	periodo := Periodo{}
+	resumo := Resumir(periodo, VendasDoPeriodo(periodo))
+	if resumo.Vendas != 1 || resumo.Unidades != 3 {
+		t.Errorf("resumo com %d venda(s) e %d unidade(s), esperado 1 e 3", resumo.Vendas, resumo.Unidades)
+	}
+	if resumo.Faturamento != entidades.Reais(7500) || resumo.Devolucoes != entidades.Reais(2500) {
+		t.Errorf("faturamento %s e devoluções %s, esperado R$ 75,00 e R$ 25,00", resumo.Faturamento, resumo.Devolucoes)
+	}
+	if resumo.TicketMedio != entidades.Reais(7500) {
+		t.Errorf("ticket médio %s, esperado R$ 75,00", resumo.TicketMedio)
+	}
+
+	produtos := PorProduto(VendasDoPeriodo(periodo))
+	if len(produtos) != 1 || produtos[0].Unidades != 3 || produtos[0].Faturamento != entidades.Reais(7500) {
+		t.Errorf("totais por produto = %+v, esperado 3 unidades e R$ 75,00", produtos)
+	}
+	diario := TotaisDiarios(VendasDoPeriodo(periodo))
+	if len(diario) != 1 || diario[0].Faturamento != entidades.Reais(7500) {
+		t.Errorf("totais diários = %+v, esperado R$ 75,00", diario)
+	}
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"bufio"
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatoData é o formato das datas digitadas pelo usuário.
+const formatoData = "02/01/2006"
+
+// lerLinha exibe o texto informado e retorna a linha digitada, sem espaços nas pontas.
+func lerLinha(scanner *bufio.Scanner, texto string) string {
+	fmt.Print(texto)
+	scanner.Scan()
+	return strings.TrimSpace(scanner.Text())
+}
+
+// lerData lê uma data no formato dd/mm/aaaa até que seja válida.
+// Uma linha vazia retorna a data zero, usada como "sem limite".
+func lerData(scanner *bufio.Scanner, texto string) time.Time {
+	for {
+		linha := lerLinha(scanner, texto)
+		if linha == "" {
+			return time.Time{}
+		}
+		data, err := time.ParseInLocation(formatoData, linha, time.Local)
+		if err == nil {
+			return data
+		}
+		fmt.Println("Data inválida. Use o formato dd/mm/aaaa.")
+	}
+}
+
+// lerDinheiro lê um valor monetário até que seja válido.
+// Uma linha vazia retorna ok = false, usado como "sem limite".
+func lerDinheiro(scanner *bufio.Scanner, texto string) (valor entidades.Dinheiro, ok bool) {
+	for {
+		linha := lerLinha(scanner, texto)
+		if linha == "" {
+			return entidades.Dinheiro{}, false
+		}
+		valor, err := entidades.ParseDinheiro(linha, entidades.MoedaPadrao)
+		if err == nil {
+			return valor, true
+		}
+		fmt.Println("Valor inválido. Tente novamente.")
+	}
+}
+
+// tamanhoPagina é a quantidade de registros exibidos por vez nas listagens filtradas.
+const tamanhoPagina = 10
+
+// listarPaginado exibe o resultado da consulta em páginas, perguntando ao usuário
+// se deseja ver a próxima enquanto houver registros.
+func listarPaginado[E entidades.Entidade](scanner *bufio.Scanner, consulta *data.Consulta[E]) {
+	fmt.Printf("\n%d registro(s) encontrado(s).\n", consulta.Contar())
+	consulta.Limite(tamanhoPagina)
+	for {
+		pagina, err := consulta.Paginar()
+		if err != nil {
+			fmt.Println("Erro ao consultar:", err)
+			return
+		}
+		for _, e := range pagina.Itens {
+			fmt.Println(e.String())
+		}
+		if pagina.ProximoCursor == "" {
+			return
+		}
+		opcao, _ := strconv.Atoi(lerLinha(scanner, "\nMostrar mais (1-SIM/0-NAO)? "))
+		if opcao != 1 {
+			return
+		}
+		consulta.Apos(pagina.ProximoCursor)
+	}
+}
+
+// lerQuantidade lê um número inteiro maior que zero até que seja válido.
+func lerQuantidade(scanner *bufio.Scanner, texto string) int {
+	for {
+		quantidade, err := strconv.Atoi(lerLinha(scanner, texto))
+		if err == nil && quantidade > 0 {
+			return quantidade
+		}
+		fmt.Println("Quantidade inválida. Tente novamente.")
+	}
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"errors"
+	"fmt"
+)
+
+// mostrarErro exibe uma mensagem amigável para um erro retornado pela camada de dados.
+// A ação descreve o que estava sendo feito, como "salvar o produto".
+func mostrarErro(acao string, err error) {
+	var conflito *data.ErroConflito
+	var unicidade *data.ErroUnicidade
+	switch {
+	case errors.As(err, &unicidade):
+		fmt.Printf("Não foi possível %s: já existe um registro com %s %q.\n", acao, unicidade.Restricao, unicidade.Chave)
+	case errors.Is(err, data.ErrVendaEncerrada):
+		fmt.Printf("Não foi possível %s: a venda já foi finalizada, cancelada ou estornada.\n", acao)
+	case errors.Is(err, entidades.ErrTransicaoInvalida), errors.Is(err, data.ErrEstoqueInsuficiente),
+		errors.Is(err, data.ErrDescontoExcedido), errors.Is(err, data.ErrPontosInsuficientes),
+		errors.Is(err, entidades.ErrPagamentoInsuficiente), errors.Is(err, data.ErrCaixaFechado),
+		errors.Is(err, data.ErrCaixaAberto):
+		fmt.Printf("Não foi possível %s: %v.\n", acao, err)
+	case errors.As(err, &conflito):
+		fmt.Printf("Não foi possível %s: o registro foi alterado por outra operação. Tente novamente.\n", acao)
+	case errors.Is(err, data.ErrNaoEncontrado):
+		fmt.Printf("Não foi possível %s: registro não encontrado.\n", acao)
+	case errors.Is(err, data.ErrDuplicado):
+		fmt.Printf("Não foi possível %s: o registro já existe.\n", acao)
+	case errors.Is(err, data.ErrInvalido):
+		fmt.Printf("Não foi possível %s: %v.\n", acao, err)
+	default:
+		fmt.Printf("Erro ao %s: %v\n", acao, err)
+	}
+}
@@ -20,7 +20,7 @@ type MenuAbstrato interface {
 func MostrarMenu(menu MenuAbstrato) {
 	scanner := bufio.NewScanner(os.Stdin)
 	for {
-		fmt.Println("\n\n\n")
+		fmt.Print("\n\n\n\n")
 		menu.MostrarTitulo()
 		menu.MostrarOpcoes()
 
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"bufio"
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MenuCaixa representa o menu de abertura, movimentação e fechamento do caixa.
+type MenuCaixa struct {
+	dao *data.DAOCaixa
+}
+
+// NewMenuCaixa cria uma nova instância de MenuCaixa.
+func NewMenuCaixa() *MenuCaixa {
+	return &MenuCaixa{
+		dao: data.GetCaixaInstance(),
+	}
+}
+
+// MostrarTitulo exibe o título do menu de caixa.
+func (m *MenuCaixa) MostrarTitulo() {
+	fmt.Println("MENU CAIXA")
+}
+
+// MostrarOpcoes exibe as opções disponíveis no menu.
+func (m *MenuCaixa) MostrarOpcoes() {
+	fmt.Println("0 -> VOLTAR")
+	fmt.Println("1 -> SITUAÇÃO")
+	fmt.Println("2 -> ABRIR CAIXA")
+	fmt.Println("3 -> SANGRIA")
+	fmt.Println("4 -> SUPRIMENTO")
+	fmt.Println("5 -> FECHAR CAIXA")
+	fmt.Println("6 -> CAIXAS ANTERIORES")
+}
+
+// MostrarMenu exibe o menu e gerencia as opções.
+func (m *MenuCaixa) MostrarMenu(scanner *bufio.Scanner) {
+	for {
+		m.MostrarTitulo()
+		m.MostrarOpcoes()
+
+		fmt.Print("INFORME A SUA OPCAO: ")
+		scanner.Scan()
+		opcao, _ := strconv.Atoi(scanner.Text())
+
+		if m.ExecutarOpcao(opcao, scanner) == 0 {
+			break
+		}
+	}
+}
+
+// ExecutarOpcao executa a opção escolhida pelo usuário.
+func (m *MenuCaixa) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
+	switch opcao {
+	case 0:
+		return 0
+	case 1:
+		m.Situacao()
+	case 2:
+		m.Abrir(scanner)
+	case 3:
+		m.Movimentar(scanner, entidades.MovimentoSangria)
+	case 4:
+		m.Movimentar(scanner, entidades.MovimentoSuprimento)
+	case 5:
+		m.Fechar(scanner)
+	case 6:
+		m.Anteriores(scanner)
+	default:
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
+	}
+	return 1
+}
+
+// Situacao exibe o caixa aberto com as suas sangrias e suprimentos.
+// Os valores esperados não são exibidos, para não comprometer a contagem cega do fechamento.
+func (m *MenuCaixa) Situacao() {
+	caixa, err := m.dao.Atual()
+	if err != nil {
+		mostrarErro("consultar o caixa", err)
+		return
+	}
+	fmt.Println(caixa.String())
+}
+
+// Abrir abre o caixa com o operador e o fundo de troco informados.
+func (m *MenuCaixa) Abrir(scanner *bufio.Scanner) {
+	operador := ""
+	for operador == "" {
+		operador = lerLinha(scanner, "\nOperador: ")
+	}
+	fundo, ok := lerDinheiro(scanner, "Fundo de troco (vazio para zero): ")
+	if !ok {
+		fundo = entidades.Reais(0)
+	}
+	caixa, err := m.dao.Abrir(operador, fundo)
+	if err != nil {
+		mostrarErro("abrir o caixa", err)
+		return
+	}
+	fmt.Printf("Caixa %d aberto com sucesso!\n", caixa.GetID())
+}
+
+// Movimentar registra uma sangria ou um suprimento no caixa aberto.
+func (m *MenuCaixa) Movimentar(scanner *bufio.Scanner, tipo entidades.TipoMovimentoCaixa) {
+	valor, ok := lerDinheiro(scanner, fmt.Sprintf("\nValor da %s (vazio para cancelar): ", tipo))
+	if !ok {
+		return
+	}
+	motivo := lerLinha(scanner, "Motivo: ")
+
+	var err error
+	if tipo == entidades.MovimentoSangria {
+		_, err = m.dao.Sangria(valor, motivo)
+	} else {
+		_, err = m.dao.Suprimento(valor, motivo)
+	}
+	if err != nil {
+		mostrarErro("registrar a "+string(tipo), err)
+		return
+	}
+	fmt.Printf("%s de %s registrado(a) com sucesso!\n", strings.ToUpper(string(tipo)), valor)
+}
+
+// Fechar faz a contagem cega do caixa aberto: o operador informa o valor contado em cada
+// forma de pagamento e, somente depois, vê a conferência com os valores esperados.
+func (m *MenuCaixa) Fechar(scanner *bufio.Scanner) {
+	if _, err := m.dao.Atual(); err != nil {
+		mostrarErro("fechar o caixa", err)
+		return
+	}
+	fmt.Println("\nInforme os valores contados (vazio para zero).")
+	contado := map[entidades.FormaPagamento]entidades.Dinheiro{}
+	for _, forma := range entidades.FormasPagamento {
+		valor, ok := lerDinheiro(scanner, fmt.Sprintf("%s: ", strings.ToUpper(forma.String())))
+		if !ok {
+			valor = entidades.Reais(0)
+		}
+		contado[forma] = valor
+	}
+
+	caixa, err := m.dao.Fechar(contado)
+	if err != nil {
+		mostrarErro("fechar o caixa", err)
+		return
+	}
+	fmt.Printf("\nCaixa %d fechado.\n", caixa.GetID())
+	fmt.Println(caixa.Conferencia())
+}
+
+// Anteriores lista os caixas fechados, dos mais recentes aos mais antigos, e exibe a
+// conferência do caixa escolhido.
+func (m *MenuCaixa) Anteriores(scanner *bufio.Scanner) {
+	fechados := m.dao.Consultar().
+		Onde(func(c *entidades.Caixa) bool { return !c.Aberto() }).
+		OrdenarPor(data.Decrescente(data.PorChave(func(c *entidades.Caixa) int64 { return c.GetID() }))).
+		Limite(tamanhoPagina).
+		Listar()
+	fmt.Println()
+	for _, c := range fechados {
+		fmt.Printf("%5d  %s a %s  %s\n", c.GetID(), c.AbertoEm.Format("02/01/2006 15:04"), c.FechadoEm.Format("02/01/2006 15:04"), c.Operador)
+	}
+	id, err := strconv.ParseInt(lerLinha(scanner, "\nID do caixa para conferência (vazio para voltar): "), 10, 64)
+	if err != nil {
+		return
+	}
+	caixa, err := m.dao.Buscar(id)
+	if err != nil {
+		mostrarErro("consultar o caixa", err)
+		return
+	}
+	fmt.Println(caixa.String())
+	if !caixa.Aberto() {
+		fmt.Println(caixa.Conferencia())
+	}
+}
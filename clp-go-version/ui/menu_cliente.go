@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"bufio"
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"strconv"
+)
+
+// MenuCliente representa o menu para gerenciamento de clientes.
+type MenuCliente struct {
+	dao           *data.DAOCliente
+	daoVenda      *data.DAOVenda
+	daoFidelidade *data.DAOFidelidade
+}
+
+// NewMenuCliente cria uma nova instância de MenuCliente.
+func NewMenuCliente() *MenuCliente {
+	return &MenuCliente{
+		dao:           data.GetClienteInstance(),
+		daoVenda:      data.GetVendaInstance(),
+		daoFidelidade: data.GetFidelidadeInstance(),
+	}
+}
+
+// MostrarTitulo exibe o título do menu de clientes.
+func (m *MenuCliente) MostrarTitulo() {
+	fmt.Println("MENU CLIENTES")
+}
+
+// MostrarOpcoes exibe as opções disponíveis no menu.
+func (m *MenuCliente) MostrarOpcoes() {
+	fmt.Println("0 -> VOLTAR")
+	fmt.Println("1 -> LISTAR")
+	fmt.Println("2 -> ADICIONAR")
+	fmt.Println("3 -> REMOVER")
+	fmt.Println("4 -> BUSCAR POR NOME")
+	fmt.Println("5 -> EDITAR")
+	fmt.Println("6 -> HISTÓRICO DE COMPRAS")
+	fmt.Println("7 -> PONTOS DE FIDELIDADE")
+}
+
+// MostrarMenu exibe o menu e gerencia as opções.
+func (m *MenuCliente) MostrarMenu(scanner *bufio.Scanner) {
+	for {
+		m.MostrarTitulo()
+		m.MostrarOpcoes()
+
+		fmt.Print("INFORME A SUA OPCAO: ")
+		scanner.Scan()
+		opcao, _ := strconv.Atoi(scanner.Text())
+
+		if m.ExecutarOpcao(opcao, scanner) == 0 {
+			break
+		}
+	}
+}
+
+// ExecutarOpcao executa a opção escolhida pelo usuário.
+func (m *MenuCliente) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
+	switch opcao {
+	case 0:
+		return 0
+	case 1:
+		m.Listar()
+	case 2:
+		m.Adicionar(scanner)
+	case 3:
+		m.Remover(scanner)
+	case 4:
+		m.BuscarPorNome(scanner)
+	case 5:
+		m.Editar(scanner)
+	case 6:
+		m.Historico(scanner)
+	case 7:
+		m.Pontos(scanner)
+	default:
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
+	}
+	return 1
+}
+
+// Listar exibe todos os clientes cadastrados no sistema.
+func (m *MenuCliente) Listar() {
+	fmt.Println(m.dao.String())
+}
+
+// Adicionar cadastra um novo cliente.
+func (m *MenuCliente) Adicionar(scanner *bufio.Scanner) {
+	nome := ""
+	for nome == "" {
+		nome = lerLinha(scanner, "\nDigite o nome: ")
+	}
+	cliente := entidades.NewCliente(nome, lerLinha(scanner, "Digite o CPF ou CNPJ: "))
+	cliente.Email = lerLinha(scanner, "Digite o e-mail (vazio para nenhum): ")
+	cliente.Telefone = lerLinha(scanner, "Digite o telefone (vazio para nenhum): ")
+
+	if err := m.dao.Adicionar(cliente); err != nil {
+		mostrarErro("salvar o cliente", err)
+		return
+	}
+	fmt.Println("Cliente adicionado com sucesso!")
+}
+
+// Remover remove um cliente com base no CPF ou CNPJ.
+// As vendas do cliente continuam registradas com o seu nome.
+func (m *MenuCliente) Remover(scanner *bufio.Scanner) {
+	cliente, err := m.dao.BuscarPorDocumento(lerLinha(scanner, "\nDigite o CPF ou CNPJ: "))
+	if err == nil {
+		err = m.dao.Remover(cliente.GetID())
+	}
+	if err != nil {
+		mostrarErro("remover o cliente", err)
+		return
+	}
+	fmt.Println("Cliente removido com sucesso!")
+}
+
+// BuscarPorNome lista os clientes cujo nome contém o texto digitado.
+func (m *MenuCliente) BuscarPorNome(scanner *bufio.Scanner) {
+	clientes := m.dao.BuscarPorNome(lerLinha(scanner, "\nDigite o nome ou parte dele: "))
+	fmt.Printf("\n%d cliente(s) encontrado(s).\n", len(clientes))
+	for _, c := range clientes {
+		fmt.Println(c.String())
+	}
+	fmt.Println()
+}
+
+// Editar altera os dados de um cliente.
+// A edição é feita sobre uma cópia; se o cliente tiver sido alterado por outra operação
+// antes da gravação, a alteração é recusada.
+func (m *MenuCliente) Editar(scanner *bufio.Scanner) {
+	encontrado, err := m.dao.BuscarPorDocumento(lerLinha(scanner, "\nDigite o CPF ou CNPJ: "))
+	if err != nil {
+		mostrarErro("editar o cliente", err)
+		return
+	}
+	cliente := encontrado.Clonar()
+	fmt.Println(cliente.String())
+
+	if nome := lerLinha(scanner, "Novo nome (vazio para manter): "); nome != "" {
+		cliente.SetNome(nome)
+	}
+	if documento := lerLinha(scanner, "Novo CPF ou CNPJ (vazio para manter): "); documento != "" {
+		cliente.SetDocumento(documento)
+	}
+	if email := lerLinha(scanner, "Novo e-mail (vazio para manter, - para remover): "); email == "-" {
+		cliente.Email = ""
+	} else if email != "" {
+		cliente.Email = email
+	}
+	if telefone := lerLinha(scanner, "Novo telefone (vazio para manter, - para remover): "); telefone == "-" {
+		cliente.Telefone = ""
+	} else if telefone != "" {
+		cliente.Telefone = telefone
+	}
+
+	if err := m.dao.Atualizar(cliente); err != nil {
+		mostrarErro("salvar o cliente", err)
+		return
+	}
+	fmt.Println("Cliente atualizado com sucesso!")
+}
+
+// Historico exibe as compras de um cliente, das mais recentes às mais antigas,
+// com a quantidade de compras e o total gasto.
+func (m *MenuCliente) Historico(scanner *bufio.Scanner) {
+	cliente, err := m.dao.BuscarPorDocumento(lerLinha(scanner, "\nDigite o CPF ou CNPJ: "))
+	if err != nil {
+		mostrarErro("consultar o cliente", err)
+		return
+	}
+
+	vendas := m.daoVenda.PorCliente(cliente.GetID())
+	fmt.Printf("\nHISTÓRICO DE COMPRAS DE %s\n", cliente.GetNome())
+	for _, v := range vendas {
+		fmt.Printf("%s  venda %5d  %-10s %12s\n", v.GetDataHora().Format("02/01/2006 15:04"), v.GetID(), v.GetStatus(), v.Total())
+	}
+	fmt.Printf("%d compra(s); total gasto: %s\n\n", len(vendas), entidades.SomarVendas(vendas))
+}
+
+// Pontos exibe o saldo de pontos de fidelidade de um cliente, o valor desse saldo
+// no resgate e o extrato dos movimentos, do mais recente ao mais antigo.
+func (m *MenuCliente) Pontos(scanner *bufio.Scanner) {
+	cliente, err := m.dao.BuscarPorDocumento(lerLinha(scanner, "\nDigite o CPF ou CNPJ: "))
+	if err != nil {
+		mostrarErro("consultar o cliente", err)
+		return
+	}
+	var saldo int64
+	extrato, err := m.daoFidelidade.Extrato(cliente.GetID())
+	if err == nil {
+		saldo, err = m.daoFidelidade.Saldo(cliente.GetID())
+	}
+	if err != nil {
+		mostrarErro("consultar os pontos", err)
+		return
+	}
+
+	fmt.Printf("\nPONTOS DE %s\n", cliente.GetNome())
+	for _, movimento := range extrato {
+		fmt.Println(movimento.String())
+	}
+	fmt.Printf("Saldo: %d ponto(s), equivalente a %s\n\n", saldo, data.GetProgramaFidelidade().ValorDosPontos(saldo))
+}
@@ -25,7 +25,7 @@ func ExecutarOpcao(menu MenuEntidade, opcao int, scanner *bufio.Scanner) int {
 	case 3:
 		menu.Remover(scanner)
 	default:
-		fmt.Println("OPÇÃO INVÁLIDA\n")
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
 	}
 	return 1
 }
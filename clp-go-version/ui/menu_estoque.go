@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"bufio"
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"strconv"
+)
+
+// MenuEstoque representa o menu para controle de estoque.
+type MenuEstoque struct {
+	daoEstoque *data.DAOEstoque
+	daoProduto *data.DAOProduto
+}
+
+// NewMenuEstoque cria uma nova instância de MenuEstoque.
+func NewMenuEstoque() *MenuEstoque {
+	return &MenuEstoque{
+		daoEstoque: data.GetEstoqueInstance(),
+		daoProduto: data.GetInstance(),
+	}
+}
+
+// MostrarTitulo exibe o título do menu de estoque.
+func (m *MenuEstoque) MostrarTitulo() {
+	fmt.Println("MENU ESTOQUE")
+}
+
+// MostrarOpcoes exibe as opções disponíveis no menu.
+func (m *MenuEstoque) MostrarOpcoes() {
+	fmt.Println("0 -> VOLTAR")
+	fmt.Println("1 -> LISTAR SALDOS")
+	fmt.Println("2 -> ENTRADA")
+	fmt.Println("3 -> SAÍDA")
+	fmt.Println("4 -> AJUSTE")
+	fmt.Println("5 -> MOVIMENTAÇÕES")
+}
+
+// MostrarMenu exibe o menu e gerencia as opções.
+func (m *MenuEstoque) MostrarMenu(scanner *bufio.Scanner) {
+	for {
+		m.MostrarTitulo()
+		m.MostrarOpcoes()
+
+		fmt.Print("INFORME A SUA OPCAO: ")
+		scanner.Scan()
+		opcao, _ := strconv.Atoi(scanner.Text())
+
+		if m.ExecutarOpcao(opcao, scanner) == 0 {
+			break
+		}
+	}
+}
+
+// ExecutarOpcao executa a opção escolhida pelo usuário.
+func (m *MenuEstoque) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
+	switch opcao {
+	case 0:
+		return 0
+	case 1:
+		m.Listar()
+	case 2:
+		m.Entrada(scanner)
+	case 3:
+		m.Saida(scanner)
+	case 4:
+		m.Ajustar(scanner)
+	case 5:
+		m.Movimentacoes(scanner)
+	default:
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
+	}
+	return 1
+}
+
+// Listar exibe o saldo em estoque de todos os produtos, em ordem alfabética.
+func (m *MenuEstoque) Listar() {
+	produtos := m.daoProduto.Consultar().OrdenarPor(data.PorChave(func(p *entidades.Produto) string {
+		return data.NormalizarTexto(p.GetNome())
+	})).Listar()
+
+	fmt.Printf("\n%-30s %8s\n", "PRODUTO", "SALDO")
+	for _, p := range produtos {
+		fmt.Printf("%-30s %8d\n", p.GetNome(), p.GetEstoque())
+	}
+	fmt.Println()
+}
+
+// Entrada registra o recebimento de mercadoria.
+func (m *MenuEstoque) Entrada(scanner *bufio.Scanner) {
+	produto, ok := m.lerProduto(scanner)
+	if !ok {
+		return
+	}
+	quantidade := lerQuantidade(scanner, "Digite a quantidade recebida: ")
+	motivo := lerLinha(scanner, "Digite o motivo (ex.: nota fiscal do fornecedor): ")
+	m.mostrarResultado(m.daoEstoque.Entrada(produto.GetID(), quantidade, motivo))
+}
+
+// Saida registra a baixa de mercadoria fora de uma venda, como perdas e avarias.
+func (m *MenuEstoque) Saida(scanner *bufio.Scanner) {
+	produto, ok := m.lerProduto(scanner)
+	if !ok {
+		return
+	}
+	quantidade := lerQuantidade(scanner, "Digite a quantidade retirada: ")
+	motivo := lerLinha(scanner, "Digite o motivo (ex.: avaria): ")
+	m.mostrarResultado(m.daoEstoque.Saida(produto.GetID(), quantidade, motivo))
+}
+
+// Ajustar corrige o saldo de um produto para a quantidade contada.
+func (m *MenuEstoque) Ajustar(scanner *bufio.Scanner) {
+	produto, ok := m.lerProduto(scanner)
+	if !ok {
+		return
+	}
+	fmt.Println("Saldo atual:", produto.GetEstoque())
+	saldo := -1
+	for saldo < 0 {
+		saldo, _ = strconv.Atoi(lerLinha(scanner, "Digite a quantidade contada: "))
+	}
+	motivo := lerLinha(scanner, "Digite o motivo (ex.: inventário): ")
+	m.mostrarResultado(m.daoEstoque.Ajustar(produto.GetID(), saldo, motivo))
+}
+
+// Movimentacoes exibe o histórico de movimentos de um produto ou de todos.
+func (m *MenuEstoque) Movimentacoes(scanner *bufio.Scanner) {
+	var produtoID int64
+	if nome := lerLinha(scanner, "\nDigite o nome do produto (vazio para todos): "); nome != "" {
+		produto, err := m.daoProduto.BuscarPorNome(nome)
+		if err != nil {
+			mostrarErro("consultar o produto", err)
+			return
+		}
+		produtoID = produto.GetID()
+	}
+
+	movimentos := m.daoEstoque.Movimentos(produtoID)
+	if len(movimentos) == 0 {
+		fmt.Println("Nenhuma movimentação encontrada.")
+		return
+	}
+	for _, movimento := range movimentos {
+		fmt.Println(movimento.String())
+	}
+	fmt.Println()
+}
+
+// lerProduto lê o nome de um produto cadastrado.
+func (m *MenuEstoque) lerProduto(scanner *bufio.Scanner) (*entidades.Produto, bool) {
+	produto, err := m.daoProduto.BuscarPorNome(lerLinha(scanner, "\nDigite o nome do produto: "))
+	if err != nil {
+		mostrarErro("consultar o produto", err)
+		return nil, false
+	}
+	return produto, true
+}
+
+// mostrarResultado informa o novo saldo após um movimento, ou o erro ocorrido.
+func (m *MenuEstoque) mostrarResultado(movimento *entidades.MovimentoEstoque, err error) {
+	if err != nil {
+		mostrarErro("movimentar o estoque", err)
+		return
+	}
+	fmt.Printf("Movimento registrado. Novo saldo de %s: %d\n", movimento.NomeProduto, movimento.Saldo)
+}
@@ -8,15 +8,25 @@ import (
 
 // MenuPrincipal representa o menu principal do sistema.
 type MenuPrincipal struct {
-	MenuProduto *MenuProduto
-	MenuVenda   *MenuVenda
+	MenuProduto   *MenuProduto
+	MenuVenda     *MenuVenda
+	MenuEstoque   *MenuEstoque
+	MenuPromocao  *MenuPromocao
+	MenuCliente   *MenuCliente
+	MenuCaixa     *MenuCaixa
+	MenuRelatorio *MenuRelatorio
 }
 
 // NewMenuPrincipal cria uma nova instância de MenuPrincipal.
 func NewMenuPrincipal() *MenuPrincipal {
 	return &MenuPrincipal{
-		MenuProduto: NewMenuProduto(),
-		MenuVenda:   NewMenuVenda(),
+		MenuProduto:   NewMenuProduto(),
+		MenuVenda:     NewMenuVenda(),
+		MenuEstoque:   NewMenuEstoque(),
+		MenuPromocao:  NewMenuPromocao(),
+		MenuCliente:   NewMenuCliente(),
+		MenuCaixa:     NewMenuCaixa(),
+		MenuRelatorio: NewMenuRelatorio(),
 	}
 }
 
@@ -30,6 +40,11 @@ func (m *MenuPrincipal) MostrarOpcoes() {
 	fmt.Println("0 -> FECHAR PROGRAMA")
 	fmt.Println("1 -> PRODUTO")
 	fmt.Println("2 -> VENDA")
+	fmt.Println("3 -> ESTOQUE")
+	fmt.Println("4 -> PROMOÇÕES")
+	fmt.Println("5 -> CLIENTE")
+	fmt.Println("6 -> CAIXA")
+	fmt.Println("7 -> RELATÓRIOS")
 }
 
 // ExecutarOpcao executa a ação correspondente à opção escolhida pelo usuário.
@@ -41,8 +56,18 @@ func (m *MenuPrincipal) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
 		m.MenuProduto.MostrarMenu(scanner)
 	case 2:
 		m.MenuVenda.MostrarMenu(scanner)
+	case 3:
+		m.MenuEstoque.MostrarMenu(scanner)
+	case 4:
+		m.MenuPromocao.MostrarMenu(scanner)
+	case 5:
+		m.MenuCliente.MostrarMenu(scanner)
+	case 6:
+		m.MenuCaixa.MostrarMenu(scanner)
+	case 7:
+		m.MenuRelatorio.MostrarMenu(scanner)
 	default:
-		fmt.Println("OPÇÃO INVÁLIDA\n")
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
 	}
 	return 1
 }
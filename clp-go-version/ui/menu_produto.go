@@ -5,6 +5,7 @@ import (
 	"clp-go-version/data"
 	"clp-go-version/entidades"
 	"fmt"
+	"os"
 	"strconv"
 )
 
@@ -31,6 +32,10 @@ func (m *MenuProduto) MostrarOpcoes() {
 	fmt.Println("1 -> LISTAR")
 	fmt.Println("2 -> ADICIONAR")
 	fmt.Println("3 -> REMOVER")
+	fmt.Println("4 -> FILTRAR POR PREÇO")
+	fmt.Println("5 -> EDITAR")
+	fmt.Println("6 -> IMPORTAR CSV")
+	fmt.Println("7 -> EXPORTAR CSV")
 }
 
 // MostrarMenu exibe o menu e gerencia as opções.
@@ -60,8 +65,16 @@ func (m *MenuProduto) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
 		m.Adicionar(scanner)
 	case 3:
 		m.Remover(scanner)
+	case 4:
+		m.Filtrar(scanner)
+	case 5:
+		m.Editar(scanner)
+	case 6:
+		m.Importar(scanner)
+	case 7:
+		m.Exportar(scanner)
 	default:
-		fmt.Println("OPÇÃO INVÁLIDA\n")
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
 	}
 	return 1
 }
@@ -74,7 +87,7 @@ func (m *MenuProduto) Listar() {
 // Adicionar adiciona um novo produto ao sistema.
 func (m *MenuProduto) Adicionar(scanner *bufio.Scanner) {
 	var nome string
-	var valor float64
+	var valor entidades.Dinheiro
 
 	for {
 		fmt.Print("\nDigite o nome: ")
@@ -83,17 +96,26 @@ func (m *MenuProduto) Adicionar(scanner *bufio.Scanner) {
 
 		fmt.Print("Digite o valor: ")
 		scanner.Scan()
-		valor, _ = strconv.ParseFloat(scanner.Text(), 64)
+		valor, _ = entidades.ParseDinheiro(scanner.Text(), entidades.MoedaPadrao)
 
-		if nome == "" || valor <= 0.0 {
-			fmt.Println("\nFavor informar os dados corretamente.\n")
+		if nome == "" || !valor.Positivo() {
+			fmt.Print("\nFavor informar os dados corretamente.\n\n")
 			continue
 		}
 		break
 	}
 
 	produto := entidades.NewProduto(nome, valor)
-	m.dao.Adicionar(produto)
+	produto.SetSKU(lerLinha(scanner, "Digite o SKU (vazio para nenhum): "))
+	produto.SetNCM(lerLinha(scanner, "Digite o NCM (vazio para nenhum): "))
+	produto.SetCFOP(lerLinha(scanner, "Digite o CFOP (vazio para nenhum; 5933 para serviços): "))
+	produto.SetCategoria(lerLinha(scanner, "Digite a categoria (vazio para nenhuma): "))
+	inicial, _ := strconv.Atoi(lerLinha(scanner, "Digite o estoque inicial (vazio para zero): "))
+	// O estoque inicial é registrado como entrada, para constar no histórico do estoque.
+	if err := m.dao.AdicionarComEstoque(produto, max(inicial, 0), "estoque inicial"); err != nil {
+		mostrarErro("salvar o produto", err)
+		return
+	}
 	fmt.Println("Produto adicionado com sucesso!")
 }
 
@@ -107,11 +129,158 @@ func (m *MenuProduto) Remover(scanner *bufio.Scanner) {
 		nome = scanner.Text()
 
 		if nome == "" {
-			fmt.Println("\nFavor informar o nome corretamente.\n")
+			fmt.Print("\nFavor informar o nome corretamente.\n\n")
 			continue
 		}
 		break
 	}
 
-	m.dao.Remover(m.dao.BuscarPorNome(nome).GetID())
+	produto, err := m.dao.BuscarPorNome(nome)
+	if err == nil {
+		err = m.dao.Remover(produto.GetID())
+	}
+	if err != nil {
+		mostrarErro("remover o produto", err)
+		return
+	}
+	fmt.Println("Produto removido com sucesso!")
+}
+
+// Filtrar lista os produtos dentro de uma faixa de preço, do mais barato ao mais caro.
+func (m *MenuProduto) Filtrar(scanner *bufio.Scanner) {
+	consulta := m.dao.Consultar().OrdenarPor(data.PorChave(func(p *entidades.Produto) int64 {
+		return p.GetValor().Centavos
+	}))
+
+	if minimo, ok := lerDinheiro(scanner, "\nValor mínimo (vazio para nenhum): "); ok {
+		consulta.Onde(func(p *entidades.Produto) bool { return p.GetValor().Comparar(minimo) >= 0 })
+	}
+	if maximo, ok := lerDinheiro(scanner, "Valor máximo (vazio para nenhum): "); ok {
+		consulta.Onde(func(p *entidades.Produto) bool { return p.GetValor().Comparar(maximo) <= 0 })
+	}
+
+	listarPaginado(scanner, consulta)
+}
+
+// Editar altera o nome, os códigos e o valor de um produto.
+// A edição é feita sobre uma cópia do produto; se ele tiver sido alterado por outra
+// operação antes da gravação, a alteração é recusada.
+func (m *MenuProduto) Editar(scanner *bufio.Scanner) {
+	encontrado, err := m.dao.BuscarPorNome(lerLinha(scanner, "\nDigite o nome: "))
+	if err != nil {
+		mostrarErro("editar o produto", err)
+		return
+	}
+	produto := encontrado.Clonar()
+	fmt.Println(produto.String())
+
+	if nome := lerLinha(scanner, "Novo nome (vazio para manter): "); nome != "" {
+		produto.SetNome(nome)
+	}
+	if sku := lerLinha(scanner, "Novo SKU (vazio para manter, - para remover): "); sku == "-" {
+		produto.SetSKU("")
+	} else if sku != "" {
+		produto.SetSKU(sku)
+	}
+	if ncm := lerLinha(scanner, "Novo NCM (vazio para manter, - para remover): "); ncm == "-" {
+		produto.SetNCM("")
+	} else if ncm != "" {
+		produto.SetNCM(ncm)
+	}
+	if cfop := lerLinha(scanner, "Novo CFOP (vazio para manter, - para remover): "); cfop == "-" {
+		produto.SetCFOP("")
+	} else if cfop != "" {
+		produto.SetCFOP(cfop)
+	}
+	if categoria := lerLinha(scanner, "Nova categoria (vazio para manter, - para remover): "); categoria == "-" {
+		produto.SetCategoria("")
+	} else if categoria != "" {
+		produto.SetCategoria(categoria)
+	}
+	for {
+		valor, ok := lerDinheiro(scanner, "Novo valor (vazio para manter): ")
+		if !ok {
+			break
+		}
+		if valor.Positivo() {
+			produto.SetValor(valor)
+			break
+		}
+		fmt.Println("O valor deve ser maior que zero.")
+	}
+
+	if err := m.dao.Atualizar(produto); err != nil {
+		mostrarErro("salvar o produto", err)
+		return
+	}
+	fmt.Println("Produto atualizado com sucesso!")
+}
+
+// Importar adiciona e atualiza produtos a partir de um arquivo CSV (ver
+// data.ImportarProdutosCSV). O arquivo é primeiro validado em uma simulação, que exibe os
+// erros de cada linha, e só é gravado após a confirmação do usuário.
+func (m *MenuProduto) Importar(scanner *bufio.Scanner) {
+	caminho := lerLinha(scanner, "\nDigite o caminho do arquivo CSV: ")
+	if caminho == "" {
+		return
+	}
+	importar := func(simular bool) (*data.ResultadoImportacao, error) {
+		arquivo, err := os.Open(caminho)
+		if err != nil {
+			return nil, err
+		}
+		defer arquivo.Close()
+		return data.ImportarProdutosCSV(arquivo, data.OpcoesCSV{Simular: simular})
+	}
+
+	simulacao, err := importar(true)
+	if err != nil {
+		mostrarErro("ler o arquivo", err)
+		return
+	}
+	fmt.Print("\n" + simulacao.String())
+	if simulacao.Adicionados+simulacao.Atualizados == 0 {
+		fmt.Println("Nenhum produto a gravar.")
+		return
+	}
+	opcao, _ := strconv.Atoi(lerLinha(scanner, "\nGravar os produtos válidos (1-SIM/0-NAO)? "))
+	if opcao != 1 {
+		return
+	}
+
+	resultado, err := importar(false)
+	if err != nil {
+		mostrarErro("importar os produtos", err)
+		return
+	}
+	fmt.Print("\n" + resultado.String())
+}
+
+// Exportar grava todos os produtos em um arquivo CSV, que pode ser editado em uma planilha
+// e importado novamente.
+func (m *MenuProduto) Exportar(scanner *bufio.Scanner) {
+	caminho := lerLinha(scanner, "\nDigite o caminho do arquivo CSV: ")
+	if caminho == "" {
+		return
+	}
+	opcoes := data.OpcoesCSV{}
+	// O separador ';' e a vírgula decimal são o padrão das planilhas em português.
+	if opcao, _ := strconv.Atoi(lerLinha(scanner, "Usar ';' e vírgula decimal, para planilhas em português (1-SIM/0-NAO)? ")); opcao == 1 {
+		opcoes.Separador = ';'
+	}
+
+	arquivo, err := os.Create(caminho)
+	if err != nil {
+		mostrarErro("criar o arquivo", err)
+		return
+	}
+	err = data.ExportarProdutosCSV(arquivo, opcoes)
+	if errFechar := arquivo.Close(); err == nil {
+		err = errFechar
+	}
+	if err != nil {
+		mostrarErro("exportar os produtos", err)
+		return
+	}
+	fmt.Println("Produtos exportados com sucesso!")
 }
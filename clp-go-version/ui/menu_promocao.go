@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"bufio"
+	"clp-go-version/data"
+	"clp-go-version/entidades"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MenuPromocao representa o menu para gerenciamento de promoções.
+type MenuPromocao struct {
+	dao        *data.DAOPromocao
+	daoProduto *data.DAOProduto
+}
+
+// NewMenuPromocao cria uma nova instância de MenuPromocao.
+func NewMenuPromocao() *MenuPromocao {
+	return &MenuPromocao{
+		dao:        data.GetPromocaoInstance(),
+		daoProduto: data.GetInstance(),
+	}
+}
+
+// MostrarTitulo exibe o título do menu de promoções.
+func (m *MenuPromocao) MostrarTitulo() {
+	fmt.Println("MENU PROMOÇÕES")
+}
+
+// MostrarOpcoes exibe as opções disponíveis no menu.
+func (m *MenuPromocao) MostrarOpcoes() {
+	fmt.Println("0 -> VOLTAR")
+	fmt.Println("1 -> LISTAR")
+	fmt.Println("2 -> ADICIONAR")
+	fmt.Println("3 -> REMOVER")
+	fmt.Println("4 -> ATIVAR/DESATIVAR")
+}
+
+// MostrarMenu exibe o menu e gerencia as opções.
+func (m *MenuPromocao) MostrarMenu(scanner *bufio.Scanner) {
+	for {
+		m.MostrarTitulo()
+		m.MostrarOpcoes()
+
+		fmt.Print("INFORME A SUA OPCAO: ")
+		scanner.Scan()
+		opcao, _ := strconv.Atoi(scanner.Text())
+
+		if m.ExecutarOpcao(opcao, scanner) == 0 {
+			break
+		}
+	}
+}
+
+// ExecutarOpcao executa a opção escolhida pelo usuário.
+func (m *MenuPromocao) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
+	switch opcao {
+	case 0:
+		return 0
+	case 1:
+		m.Listar()
+	case 2:
+		m.Adicionar(scanner)
+	case 3:
+		m.Remover(scanner)
+	case 4:
+		m.AlternarAtiva(scanner)
+	default:
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
+	}
+	return 1
+}
+
+// Listar exibe todas as promoções cadastradas, da maior para a menor prioridade.
+func (m *MenuPromocao) Listar() {
+	promocoes := m.dao.Consultar().OrdenarPor(data.Decrescente(data.PorChave(func(p *entidades.Promocao) int {
+		return p.Prioridade
+	}))).Listar()
+	for _, p := range promocoes {
+		fmt.Println(p.String())
+	}
+	fmt.Println()
+}
+
+// Adicionar cadastra uma nova promoção.
+func (m *MenuPromocao) Adicionar(scanner *bufio.Scanner) {
+	nome := ""
+	for nome == "" {
+		nome = lerLinha(scanner, "\nDigite o nome da promoção: ")
+	}
+
+	fmt.Println("1 -> LEVE E PAGUE")
+	fmt.Println("2 -> COMBO")
+	fmt.Println("3 -> DESCONTO")
+	var promocao *entidades.Promocao
+	for promocao == nil {
+		switch opcao, _ := strconv.Atoi(lerLinha(scanner, "Digite o tipo: ")); opcao {
+		case 1:
+			promocao = entidades.NewPromocao(nome, entidades.PromocaoLevePague)
+			promocao.Leve = lerQuantidade(scanner, "Leve quantas unidades: ")
+			promocao.Pague = lerQuantidade(scanner, "Pague quantas unidades: ")
+		case 2:
+			promocao = entidades.NewPromocao(nome, entidades.PromocaoCombo)
+		case 3:
+			promocao = entidades.NewPromocao(nome, entidades.PromocaoDesconto)
+			for {
+				desconto, err := entidades.ParseDesconto(lerLinha(scanner, "Desconto (ex.: 10% ou 1,50 por unidade): "))
+				if err == nil && !desconto.Vazio() {
+					promocao.Desconto = desconto
+					break
+				}
+				fmt.Println("Desconto inválido. Tente novamente.")
+			}
+		default:
+			fmt.Println("Tipo inválido. Tente novamente.")
+		}
+	}
+
+	if !m.lerProdutos(scanner, promocao) {
+		return
+	}
+	if promocao.Tipo == entidades.PromocaoCombo {
+		for !promocao.PrecoCombo.Positivo() {
+			promocao.PrecoCombo, _ = lerDinheiro(scanner, "Preço do combo: ")
+		}
+	} else {
+		promocao.Categoria = lerLinha(scanner, "Categoria abrangida (vazio para nenhuma): ")
+	}
+
+	promocao.Prioridade, _ = strconv.Atoi(lerLinha(scanner, "Prioridade (maior é avaliada antes; vazio para 0): "))
+	cumulativa, _ := strconv.Atoi(lerLinha(scanner, "Acumula com outras promoções (1-SIM/0-NAO)? "))
+	promocao.Cumulativa = cumulativa == 1
+	promocao.Inicio = lerLinha(scanner, "Início do horário HH:MM (vazio para o dia todo): ")
+	if promocao.Inicio != "" {
+		promocao.Fim = lerLinha(scanner, "Fim do horário HH:MM: ")
+	}
+
+	if err := m.dao.Adicionar(promocao); err != nil {
+		mostrarErro("salvar a promoção", err)
+		return
+	}
+	fmt.Println("Promoção adicionada com sucesso!")
+}
+
+// lerProdutos lê os nomes dos produtos abrangidos pela promoção, separados por vírgula.
+// Retorna false se algum produto não for encontrado.
+func (m *MenuPromocao) lerProdutos(scanner *bufio.Scanner, promocao *entidades.Promocao) bool {
+	texto := "Produtos abrangidos, separados por vírgula (vazio para nenhum): "
+	if promocao.Tipo == entidades.PromocaoCombo {
+		texto = "Produtos do combo, separados por vírgula: "
+	}
+	for _, nome := range strings.Split(lerLinha(scanner, texto), ",") {
+		if nome = strings.TrimSpace(nome); nome == "" {
+			continue
+		}
+		produto, err := m.daoProduto.BuscarPorNome(nome)
+		if err != nil {
+			mostrarErro("incluir o produto na promoção", err)
+			return false
+		}
+		promocao.ProdutoIDs = append(promocao.ProdutoIDs, produto.GetID())
+	}
+	return true
+}
+
+// Remover remove uma promoção com base no ID.
+func (m *MenuPromocao) Remover(scanner *bufio.Scanner) {
+	id, _ := strconv.ParseInt(lerLinha(scanner, "\nDigite o id: "), 10, 64)
+	if err := m.dao.Remover(id); err != nil {
+		mostrarErro("remover a promoção", err)
+		return
+	}
+	fmt.Println("Promoção removida com sucesso!")
+}
+
+// AlternarAtiva ativa uma promoção inativa ou desativa uma ativa.
+func (m *MenuPromocao) AlternarAtiva(scanner *bufio.Scanner) {
+	id, _ := strconv.ParseInt(lerLinha(scanner, "\nDigite o id: "), 10, 64)
+	encontrada, err := m.dao.Buscar(id)
+	if err != nil {
+		mostrarErro("alterar a promoção", err)
+		return
+	}
+	promocao := encontrada.Clonar()
+	promocao.Ativa = !promocao.Ativa
+	if err := m.dao.Atualizar(promocao); err != nil {
+		mostrarErro("alterar a promoção", err)
+		return
+	}
+	fmt.Println(promocao.String())
+}
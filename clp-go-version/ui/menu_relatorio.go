@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"bufio"
+	"clp-go-version/entidades"
+	"clp-go-version/relatorios"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// MenuRelatorio representa o menu de relatórios de vendas e de análise do catálogo.
+// Os relatórios usam o período selecionado, que começa como o mês atual.
+type MenuRelatorio struct {
+	periodo relatorios.Periodo
+	analise relatorios.OpcoesAnalise // Limites da análise do catálogo, alterados nas opções de estoque parado e compra.
+}
+
+// NewMenuRelatorio cria uma nova instância de MenuRelatorio.
+func NewMenuRelatorio() *MenuRelatorio {
+	return &MenuRelatorio{
+		periodo: relatorios.Mes(time.Now()),
+		analise: relatorios.OpcoesAnalisePadrao(time.Now()),
+	}
+}
+
+// MostrarTitulo exibe o título do menu de relatórios, com o período selecionado.
+func (m *MenuRelatorio) MostrarTitulo() {
+	fmt.Printf("MENU RELATÓRIOS (%s)\n", m.periodo)
+}
+
+// MostrarOpcoes exibe as opções disponíveis no menu.
+func (m *MenuRelatorio) MostrarOpcoes() {
+	fmt.Println("0 -> VOLTAR")
+	fmt.Println("1 -> ALTERAR PERÍODO")
+	fmt.Println("2 -> RESUMO")
+	fmt.Println("3 -> VENDAS POR DIA")
+	fmt.Println("4 -> VENDAS POR PRODUTO")
+	fmt.Println("5 -> VENDAS POR HORA")
+	fmt.Println("6 -> COMPARAR COM O PERÍODO ANTERIOR")
+	fmt.Println("7 -> EXPORTAR CSV")
+	fmt.Println("8 -> EXPORTAR JSON")
+	fmt.Println("9 -> CURVA ABC")
+	fmt.Println("10 -> ESTOQUE PARADO")
+	fmt.Println("11 -> SUGESTÃO DE COMPRA")
+}
+
+// MostrarMenu exibe o menu e gerencia as opções.
+func (m *MenuRelatorio) MostrarMenu(scanner *bufio.Scanner) {
+	for {
+		m.MostrarTitulo()
+		m.MostrarOpcoes()
+
+		fmt.Print("INFORME A SUA OPCAO: ")
+		scanner.Scan()
+		opcao, _ := strconv.Atoi(scanner.Text())
+
+		if m.ExecutarOpcao(opcao, scanner) == 0 {
+			break
+		}
+	}
+}
+
+// ExecutarOpcao executa a opção escolhida pelo usuário.
+func (m *MenuRelatorio) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
+	vendas := func() []*entidades.Venda { return relatorios.VendasDoPeriodo(m.periodo) }
+	switch opcao {
+	case 0:
+		return 0
+	case 1:
+		m.AlterarPeriodo(scanner)
+	case 2:
+		fmt.Println("\n" + relatorios.Resumir(m.periodo, vendas()).Tabela().String())
+	case 3:
+		fmt.Println("\n" + relatorios.TabelaDiaria(m.periodo, relatorios.TotaisDiarios(vendas())).String())
+	case 4:
+		fmt.Println("\n" + relatorios.TabelaProdutos(m.periodo, relatorios.PorProduto(vendas())).String())
+	case 5:
+		fmt.Println("\n" + relatorios.TabelaHoras(m.periodo, relatorios.PorHora(vendas())).String())
+	case 6:
+		m.Comparar()
+	case 7:
+		m.ExportarCSV(scanner)
+	case 8:
+		m.ExportarJSON(scanner)
+	case 9:
+		m.CurvaABC()
+	case 10:
+		m.EstoqueParado(scanner)
+	case 11:
+		m.SugestaoCompra(scanner)
+	default:
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
+	}
+	return 1
+}
+
+// AlterarPeriodo seleciona o período dos relatórios. As datas vazias mantêm o período atual.
+func (m *MenuRelatorio) AlterarPeriodo(scanner *bufio.Scanner) {
+	inicial := lerData(scanner, "\nData inicial (dd/mm/aaaa, vazio para manter): ")
+	final := lerData(scanner, "Data final (dd/mm/aaaa, vazio para manter): ")
+	periodo := m.periodo
+	if !inicial.IsZero() {
+		periodo.Inicio = relatorios.Dia(inicial).Inicio
+	}
+	if !final.IsZero() {
+		periodo.Fim = relatorios.Dia(final).Fim
+	}
+	if !periodo.Fim.After(periodo.Inicio) {
+		fmt.Print("\nA data final não pode ser anterior à inicial.\n\n")
+		return
+	}
+	m.periodo = periodo
+}
+
+// Comparar exibe os indicadores do período selecionado ao lado dos do período anterior de
+// mesma duração.
+func (m *MenuRelatorio) Comparar() {
+	fmt.Println("\n" + relatorios.Comparar(m.periodo, m.periodo.Anterior()).Tabela().String())
+}
+
+// CurvaABC exibe o resumo por classe e a curva ABC dos produtos no período.
+func (m *MenuRelatorio) CurvaABC() {
+	tabelas := m.analisar().Tabelas()
+	fmt.Println("\n" + tabelas[0].String())
+	fmt.Println(tabelas[1].String())
+}
+
+// EstoqueParado exibe os produtos com estoque sem vendas há mais dias que o informado.
+func (m *MenuRelatorio) EstoqueParado(scanner *bufio.Scanner) {
+	if dias, _ := strconv.Atoi(lerLinha(scanner, fmt.Sprintf("\nDias sem venda (vazio para %d): ", m.analise.DiasParado))); dias > 0 {
+		m.analise.DiasParado = dias
+	}
+	analise := m.analisar()
+	fmt.Println("\n" + relatorios.TabelaParados(analise.Opcoes.DiasParado, analise.Parados).String())
+}
+
+// SugestaoCompra exibe os produtos cujo estoque não cobre os dias de venda informados,
+// pela média de vendas do período.
+func (m *MenuRelatorio) SugestaoCompra(scanner *bufio.Scanner) {
+	if dias, _ := strconv.Atoi(lerLinha(scanner, fmt.Sprintf("\nDias de venda a cobrir (vazio para %d): ", m.analise.DiasCobertura))); dias > 0 {
+		m.analise.DiasCobertura = dias
+	}
+	analise := m.analisar()
+	fmt.Println("\n" + relatorios.TabelaSugestoes(analise.Opcoes.DiasCobertura, analise.Sugestoes).String())
+}
+
+// analisar analisa o catálogo com as vendas do período selecionado.
+func (m *MenuRelatorio) analisar() *relatorios.Analise {
+	opcoes := m.analise
+	opcoes.Periodo = m.periodo
+	return relatorios.Analisar(opcoes)
+}
+
+// ExportarCSV grava um dos relatórios do período em um arquivo CSV.
+func (m *MenuRelatorio) ExportarCSV(scanner *bufio.Scanner) {
+	tabelas := append(relatorios.Gerar(m.periodo).Tabelas(), m.analisar().Tabelas()...)
+	fmt.Println()
+	for i, t := range tabelas {
+		fmt.Printf("%d -> %s\n", i+1, t.Titulo)
+	}
+	opcao, _ := strconv.Atoi(lerLinha(scanner, "Relatório a exportar (0 para cancelar): "))
+	if opcao < 1 || opcao > len(tabelas) {
+		return
+	}
+	var separador rune
+	// O separador ';' e a vírgula decimal são o padrão das planilhas em português.
+	if sim, _ := strconv.Atoi(lerLinha(scanner, "Usar ';' e vírgula decimal, para planilhas em português (1-SIM/0-NAO)? ")); sim == 1 {
+		separador = ';'
+	}
+	m.exportar(scanner, func(w io.Writer) error { return tabelas[opcao-1].EscreverCSV(w, separador) })
+}
+
+// ExportarJSON grava todos os relatórios do período em um arquivo JSON.
+func (m *MenuRelatorio) ExportarJSON(scanner *bufio.Scanner) {
+	relatorio := relatorios.Gerar(m.periodo)
+	m.exportar(scanner, relatorio.EscreverJSON)
+}
+
+// exportar pede o caminho do arquivo e o grava com a função informada.
+func (m *MenuRelatorio) exportar(scanner *bufio.Scanner, escrever func(io.Writer) error) {
+	caminho := lerLinha(scanner, "Digite o caminho do arquivo: ")
+	if caminho == "" {
+		return
+	}
+	arquivo, err := os.Create(caminho)
+	if err != nil {
+		mostrarErro("criar o arquivo", err)
+		return
+	}
+	err = escrever(arquivo)
+	if errFechar := arquivo.Close(); err == nil {
+		err = errFechar
+	}
+	if err != nil {
+		mostrarErro("exportar o relatório", err)
+		return
+	}
+	fmt.Print("Relatório exportado com sucesso!\n\n")
+}
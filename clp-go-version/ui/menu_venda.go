@@ -2,8 +2,11 @@ package ui
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"clp-go-version/data"
 	"clp-go-version/entidades"
@@ -11,15 +14,25 @@ import (
 
 // MenuVenda representa o menu para gerenciamento de vendas.
 type MenuVenda struct {
-	daoVenda   *data.DAOVenda
-	daoProduto *data.DAOProduto
+	daoVenda      *data.DAOVenda
+	daoProduto    *data.DAOProduto
+	daoDevolucao  *data.DAODevolucao
+	daoPromocao   *data.DAOPromocao
+	daoCliente    *data.DAOCliente
+	daoFidelidade *data.DAOFidelidade
+	daoCaixa      *data.DAOCaixa
 }
 
 // NewMenuVenda cria uma nova instância de MenuVenda.
 func NewMenuVenda() *MenuVenda {
 	return &MenuVenda{
-		daoVenda:   data.GetVendaInstance(),
-		daoProduto: data.GetInstance(),
+		daoVenda:      data.GetVendaInstance(),
+		daoProduto:    data.GetInstance(),
+		daoDevolucao:  data.GetDevolucaoInstance(),
+		daoPromocao:   data.GetPromocaoInstance(),
+		daoCliente:    data.GetClienteInstance(),
+		daoFidelidade: data.GetFidelidadeInstance(),
+		daoCaixa:      data.GetCaixaInstance(),
 	}
 }
 
@@ -33,7 +46,12 @@ func (m *MenuVenda) MostrarOpcoes() {
 	fmt.Println("0 -> VOLTAR")
 	fmt.Println("1 -> LISTAR")
 	fmt.Println("2 -> ADICIONAR")
-	fmt.Println("3 -> REMOVER")
+	fmt.Println("3 -> CANCELAR")
+	fmt.Println("4 -> FILTRAR")
+	fmt.Println("5 -> EDITAR")
+	fmt.Println("6 -> FINALIZAR")
+	fmt.Println("7 -> ESTORNAR")
+	fmt.Println("8 -> DEVOLVER ITENS")
 }
 
 // MostrarMenu exibe o menu e gerencia as opções.
@@ -62,77 +80,488 @@ func (m *MenuVenda) ExecutarOpcao(opcao int, scanner *bufio.Scanner) int {
 	case 2:
 		m.Adicionar(scanner)
 	case 3:
-		m.Remover(scanner)
+		m.Cancelar(scanner)
+	case 4:
+		m.Filtrar(scanner)
+	case 5:
+		m.Editar(scanner)
+	case 6:
+		m.Finalizar(scanner)
+	case 7:
+		m.Estornar(scanner)
+	case 8:
+		m.Devolver(scanner)
 	default:
-		fmt.Println("OPÇÃO INVÁLIDA\n")
+		fmt.Print("OPÇÃO INVÁLIDA\n\n")
 	}
 	return 1
 }
 
-// Listar exibe todas as vendas cadastradas no sistema.
+// Listar exibe todas as vendas cadastradas no sistema e o total faturado.
 func (m *MenuVenda) Listar() {
 	fmt.Println(m.daoVenda.String())
+	fmt.Printf("TOTAL FATURADO: %s\n\n", m.daoVenda.Faturamento(nil))
 }
 
 // Adicionar adiciona uma nova venda ao sistema.
+// A venda é gravada aberta e pode ser finalizada em seguida ou mais tarde, pela opção FINALIZAR.
+// Exige um caixa aberto, ao qual a venda é atribuída.
 func (m *MenuVenda) Adicionar(scanner *bufio.Scanner) {
+	if _, err := m.daoCaixa.Atual(); err != nil {
+		mostrarErro("iniciar a venda", err)
+		return
+	}
 	venda := entidades.NewVenda()
+	venda.SetCliente(m.lerCliente(scanner))
 
 	for {
-		var produto *entidades.Produto
-		var qtd int
+		produto, qtd := m.lerItem(scanner, venda, nil)
+		venda.AdicionarItem(*produto, qtd)
+		m.aplicarPromocoes(venda)
+		m.lerDesconto(scanner, venda, "Desconto no item (ex.: 10% ou 5,00; vazio para nenhum): ", descontoNoUltimoItem)
 
-		for {
-			fmt.Print("\nDigite o nome do produto: ")
-			scanner.Scan()
-			nomeProduto := scanner.Text()
+		fmt.Print("\nDeseja adicionar outro produto à venda (1-SIM/0-NAO)? ")
+		scanner.Scan()
+		opcao, _ := strconv.Atoi(scanner.Text())
+		if opcao != 1 {
+			break
+		}
+	}
+	m.lerDesconto(scanner, venda, "Desconto na venda (ex.: 10% ou 5,00; vazio para nenhum): ", (*entidades.Venda).AplicarDesconto)
+	m.lerResgate(scanner, venda)
 
-			produto = m.daoProduto.BuscarPorNome(nomeProduto)
-			if produto == nil {
-				fmt.Println("Produto não encontrado. Tente novamente.")
-				continue
-			}
+	if err := m.daoVenda.Adicionar(venda); err != nil {
+		mostrarErro("salvar a venda", err)
+		return
+	}
+
+	opcao, _ := strconv.Atoi(lerLinha(scanner, "\nDeseja finalizar a venda agora (1-SIM/0-NAO)? "))
+	if opcao != 1 {
+		fmt.Printf("Venda %d salva em aberto.\n", venda.GetID())
+		return
+	}
+	m.finalizar(scanner, venda.GetID())
+}
+
+// Finalizar conclui uma venda aberta e emite a nota fiscal.
+func (m *MenuVenda) Finalizar(scanner *bufio.Scanner) {
+	m.finalizar(scanner, m.lerID(scanner))
+}
 
-			fmt.Print("Digite a quantidade: ")
-			scanner.Scan()
-			qtd, _ = strconv.Atoi(scanner.Text())
+// finalizar lê os pagamentos da venda com o ID informado, conclui a venda e emite a nota fiscal.
+func (m *MenuVenda) finalizar(scanner *bufio.Scanner, id int64) {
+	encontrada, err := m.daoVenda.Buscar(id)
+	if err == nil && !encontrada.Aberta() {
+		err = &entidades.ErroTransicao{De: encontrada.GetStatus(), Para: entidades.VendaFinalizada}
+	}
+	if err == nil {
+		_, err = m.daoCaixa.Atual()
+	}
+	if err != nil {
+		mostrarErro("finalizar a venda", err)
+		return
+	}
+	pagamentos, ok := m.lerPagamentos(scanner, encontrada.Clonar())
+	if !ok {
+		fmt.Printf("Pagamento cancelado. A venda %d continua aberta.\n", id)
+		return
+	}
+	venda, err := m.daoVenda.Finalizar(id, pagamentos)
+	if err != nil {
+		mostrarErro("finalizar a venda", err)
+		return
+	}
+	fmt.Println("\n\nNOTA FISCAL\n", venda.String())
+}
+
+// lerPagamentos lê as formas de pagamento da venda até que cubram o total, informando o
+// troco quando houver. Retorna ok = false se o operador cancelar o pagamento.
+func (m *MenuVenda) lerPagamentos(scanner *bufio.Scanner, venda *entidades.Venda) (pagamentos []entidades.Pagamento, ok bool) {
+	venda.RemoverPagamentos()
+	fmt.Println("\nTOTAL A PAGAR:", venda.Total())
+	for venda.Restante().Positivo() {
+		restante := venda.Restante()
+		fmt.Println("\nRESTANTE:", restante)
+		for i, forma := range entidades.FormasPagamento {
+			fmt.Printf("%d -> %s\n", i+1, strings.ToUpper(forma.String()))
+		}
+		fmt.Println("0 -> CANCELAR")
 
-			if qtd <= 0 {
-				fmt.Println("Quantidade inválida. Tente novamente.")
+		opcao, _ := strconv.Atoi(lerLinha(scanner, "FORMA DE PAGAMENTO: "))
+		if opcao == 0 {
+			return nil, false
+		}
+		if opcao < 0 || opcao > len(entidades.FormasPagamento) {
+			fmt.Print("OPÇÃO INVÁLIDA\n\n")
+			continue
+		}
+		forma := entidades.FormasPagamento[opcao-1]
+		valor, informado := lerDinheiro(scanner, fmt.Sprintf("Valor (vazio para %s): ", restante))
+		if !informado {
+			valor = restante
+		}
+		pagamento := entidades.NewPagamento(forma, valor)
+		if forma == entidades.PagamentoCredito {
+			pagamento = entidades.NewPagamentoParcelado(valor, lerQuantidade(scanner, "Quantidade de parcelas: "))
+		}
+		if err := venda.AdicionarPagamento(pagamento); err != nil {
+			fmt.Println("Pagamento inválido:", err)
+		}
+	}
+	if troco := venda.Troco(); troco.Positivo() {
+		fmt.Println("TROCO:", troco)
+	}
+	return venda.Pagamentos, true
+}
+
+// lerResgate oferece ao cliente da venda o resgate de pontos de fidelidade como desconto,
+// limitado ao saldo do cliente e ao total da venda. Os pontos são debitados na finalização.
+// Uma linha vazia desfaz o resgate.
+func (m *MenuVenda) lerResgate(scanner *bufio.Scanner, venda *entidades.Venda) {
+	if venda.ClienteID == 0 {
+		return
+	}
+	saldo, err := m.daoFidelidade.Saldo(venda.ClienteID)
+	if err != nil || saldo <= 0 {
+		return
+	}
+	programa := data.GetProgramaFidelidade()
+	fmt.Printf("\nO cliente tem %d ponto(s), equivalente a %s.\n", saldo, programa.ValorDosPontos(saldo))
+	for {
+		linha := lerLinha(scanner, "Pontos a resgatar (vazio para nenhum): ")
+		if linha == "" {
+			venda.ResgatarPontos(0, entidades.Dinheiro{})
+			return
+		}
+		pontos, err := strconv.ParseInt(linha, 10, 64)
+		switch {
+		case err != nil || pontos <= 0:
+			fmt.Println("Quantidade inválida. Tente novamente.")
+		case pontos > saldo:
+			fmt.Printf("O cliente tem apenas %d ponto(s).\n", saldo)
+		default:
+			if err := venda.ResgatarPontos(pontos, programa.ValorDosPontos(pontos)); err != nil {
+				fmt.Println("Resgate inválido:", err)
 				continue
 			}
-			break
+			fmt.Println("Total com o resgate:", venda.Total())
+			return
 		}
+	}
+}
 
-		venda.AdicionarItem(*produto, qtd)
+// lerCliente lê o CPF ou CNPJ do cliente da venda, que é opcional.
+// Retorna nil se nenhum documento for informado.
+func (m *MenuVenda) lerCliente(scanner *bufio.Scanner) *entidades.Cliente {
+	for {
+		documento := lerLinha(scanner, "\nCPF ou CNPJ do cliente (vazio para nenhum): ")
+		if documento == "" {
+			return nil
+		}
+		cliente, err := m.daoCliente.BuscarPorDocumento(documento)
+		if err == nil {
+			fmt.Println("Cliente:", cliente.GetNome())
+			return cliente
+		}
+		fmt.Println("Cliente não cadastrado. Cadastre-o no menu de clientes ou deixe em branco.")
+	}
+}
 
-		fmt.Print("\nDeseja adicionar outro produto à venda (1-SIM/0-NAO)? ")
+// lerItem lê o nome de um produto cadastrado e a quantidade a incluir na venda.
+// Avisa e pede outra quantidade quando o estoque não é suficiente, considerando os itens
+// já incluídos na venda e, na edição, as quantidades da venda original, que voltariam ao estoque.
+func (m *MenuVenda) lerItem(scanner *bufio.Scanner, venda, original *entidades.Venda) (*entidades.Produto, int) {
+	for {
+		fmt.Print("\nDigite o nome do produto: ")
 		scanner.Scan()
-		opcao, _ := strconv.Atoi(scanner.Text())
-		if opcao != 1 {
+		nomeProduto := scanner.Text()
+
+		produto, err := m.daoProduto.BuscarPorNome(nomeProduto)
+		if err != nil {
+			fmt.Println("Produto não encontrado. Tente novamente.")
+			continue
+		}
+
+		fmt.Print("Digite a quantidade: ")
+		scanner.Scan()
+		qtd, _ := strconv.Atoi(scanner.Text())
+
+		if qtd <= 0 {
+			fmt.Println("Quantidade inválida. Tente novamente.")
+			continue
+		}
+
+		disponivel := produto.GetEstoque() - venda.QuantidadeDoProduto(produto.GetID())
+		if original != nil {
+			disponivel += original.QuantidadeDoProduto(produto.GetID())
+		}
+		if qtd > disponivel {
+			fmt.Printf("Estoque insuficiente de %s: disponível %d. Tente novamente.\n", produto.GetNome(), max(disponivel, 0))
+			continue
+		}
+		return produto, qtd
+	}
+}
+
+// Cancelar anula uma venda aberta ou finalizada, devolvendo seus itens ao estoque.
+// A venda permanece no histórico com o motivo do cancelamento.
+func (m *MenuVenda) Cancelar(scanner *bufio.Scanner) {
+	id := m.lerID(scanner)
+	venda, err := m.daoVenda.Cancelar(id, m.lerMotivo(scanner))
+	if err != nil {
+		mostrarErro("cancelar a venda", err)
+		return
+	}
+	fmt.Printf("Venda %d cancelada com sucesso!\n", venda.GetID())
+}
+
+// Estornar desfaz uma venda finalizada, devolvendo seus itens ao estoque.
+// A venda permanece no histórico com o motivo do estorno.
+func (m *MenuVenda) Estornar(scanner *bufio.Scanner) {
+	id := m.lerID(scanner)
+	venda, err := m.daoVenda.Estornar(id, m.lerMotivo(scanner))
+	if err != nil {
+		mostrarErro("estornar a venda", err)
+		return
+	}
+	// O valor já creditado em devoluções anteriores não é devolvido outra vez.
+	reembolso := venda.Total()
+	for _, devolucao := range m.daoDevolucao.PorVenda(venda.GetID()) {
+		reembolso = reembolso.Subtrair(devolucao.Total())
+	}
+	fmt.Printf("Venda %d estornada com sucesso! Devolver %s ao cliente.\n", venda.GetID(), reembolso)
+}
+
+// Devolver registra a devolução de parte dos itens de uma venda finalizada, devolve os
+// produtos ao estoque e emite a nota de crédito. A venda original não é alterada.
+func (m *MenuVenda) Devolver(scanner *bufio.Scanner) {
+	venda, err := m.daoVenda.Buscar(m.lerID(scanner))
+	if err != nil {
+		mostrarErro("consultar a venda", err)
+		return
+	}
+	anteriores := m.daoDevolucao.PorVenda(venda.GetID())
+	restantes := entidades.QuantidadesDevolviveis(venda, anteriores)
+	for _, anterior := range anteriores {
+		fmt.Printf("Devolução %d em %s: %s\n", anterior.GetID(), anterior.DataHora.Format("2006-01-02 15:04:05"), anterior.Total())
+	}
+
+	devolucao := entidades.NewDevolucao(venda, "")
+	for {
+		fmt.Println("\nItens da venda:")
+		for i, item := range venda.GetItens() {
+			fmt.Printf("%3d  %s  (pode devolver %d)\n", i+1, item.String(), restantes[i])
+		}
+		posicao, _ := strconv.Atoi(lerLinha(scanner, "Digite o número do item (0 para concluir): "))
+		if posicao == 0 {
 			break
 		}
+		if posicao < 1 || posicao > len(restantes) {
+			fmt.Println("Item inválido. Tente novamente.")
+			continue
+		}
+		quantidade := lerQuantidade(scanner, "Digite a quantidade devolvida: ")
+		if quantidade > restantes[posicao-1] {
+			fmt.Printf("Só é possível devolver %d unidade(s) deste item.\n", restantes[posicao-1])
+			continue
+		}
+		devolucao.AdicionarItem(venda, posicao-1, quantidade)
+		restantes[posicao-1] -= quantidade
 	}
+	if len(devolucao.GetItens()) == 0 {
+		fmt.Println("Nenhum item devolvido.")
+		return
+	}
+	devolucao.Motivo = m.lerMotivo(scanner)
 
-	fmt.Println("\n\nNOTA FISCAL\n", venda.String())
-	m.daoVenda.Adicionar(venda)
+	if err := m.daoDevolucao.Adicionar(devolucao); err != nil {
+		mostrarErro("registrar a devolução", err)
+		return
+	}
+	fmt.Println("\n\nNOTA DE CRÉDITO\n", devolucao.String())
 }
 
-// Remover remove uma venda com base no ID.
-func (m *MenuVenda) Remover(scanner *bufio.Scanner) {
-	var id int64
+// lerID lê o ID de uma venda até que seja válido.
+func (m *MenuVenda) lerID(scanner *bufio.Scanner) int64 {
+	for {
+		id, _ := strconv.ParseInt(lerLinha(scanner, "\nDigite o id: "), 10, 64)
+		if id > 0 {
+			return id
+		}
+		fmt.Println("ID inválido. Tente novamente.")
+	}
+}
 
+// lerMotivo lê o motivo de um cancelamento ou estorno até que seja informado.
+func (m *MenuVenda) lerMotivo(scanner *bufio.Scanner) string {
 	for {
-		fmt.Print("\nDigite o id: ")
-		scanner.Scan()
-		id, _ = strconv.ParseInt(scanner.Text(), 10, 64)
+		if motivo := lerLinha(scanner, "Digite o motivo: "); motivo != "" {
+			return motivo
+		}
+		fmt.Println("O motivo é obrigatório.")
+	}
+}
+
+// Filtrar lista as vendas de um período, status e valor mínimo, das mais recentes às mais
+// antigas, e informa o total faturado entre elas.
+func (m *MenuVenda) Filtrar(scanner *bufio.Scanner) {
+	consulta := m.daoVenda.Consultar().OrdenarPor(data.Decrescente(data.PorChave(func(v *entidades.Venda) int64 {
+		return v.GetDataHora().UnixNano()
+	})))
+
+	if inicio := lerData(scanner, "\nData inicial dd/mm/aaaa (vazio para nenhuma): "); !inicio.IsZero() {
+		consulta.Onde(func(v *entidades.Venda) bool { return !v.GetDataHora().Before(inicio) })
+	}
+	if fim := lerData(scanner, "Data final dd/mm/aaaa (vazio para nenhuma): "); !fim.IsZero() {
+		fim = fim.AddDate(0, 0, 1) // A data final inclui o dia inteiro.
+		consulta.Onde(func(v *entidades.Venda) bool { return v.GetDataHora().Before(fim) })
+	}
+	if minimo, ok := lerDinheiro(scanner, "Valor mínimo (vazio para nenhum): "); ok {
+		consulta.Onde(func(v *entidades.Venda) bool { return v.Total().Comparar(minimo) >= 0 })
+	}
+	if status := entidades.StatusVenda(strings.ToLower(lerLinha(scanner, "Status: aberta, finalizada, cancelada ou estornada (vazio para todos): "))); status != "" {
+		consulta.Onde(func(v *entidades.Venda) bool { return v.GetStatus() == status })
+	}
 
-		if id <= 0 {
-			fmt.Println("ID inválido. Tente novamente.")
+	fmt.Println("\nTOTAL FATURADO:", entidades.SomarVendas(consulta.Listar()))
+	listarPaginado(scanner, consulta)
+}
+
+// Editar altera os itens de uma venda aberta.
+// A edição é feita sobre uma cópia, gravada somente ao final; se a venda tiver sido
+// alterada por outra operação nesse meio tempo, a gravação é recusada.
+func (m *MenuVenda) Editar(scanner *bufio.Scanner) {
+	encontrada, err := m.daoVenda.Buscar(m.lerID(scanner))
+	if err == nil && !encontrada.Aberta() {
+		err = data.ErrVendaEncerrada
+	}
+	if err != nil {
+		mostrarErro("editar a venda", err)
+		return
+	}
+	venda := encontrada.Clonar()
+
+	for {
+		fmt.Println("\nItens:")
+		for i, item := range venda.GetItens() {
+			fmt.Printf("%3d  %s\n", i+1, item.String())
+			if len(item.Promocoes) > 0 {
+				fmt.Printf("     promoção %s: %s\n", strings.Join(item.Promocoes, " + "), item.DescontoPromocao.Multiplicar(-1))
+			}
+		}
+		if !venda.Desconto.Vazio() {
+			fmt.Printf("DESCONTO NA VENDA (%s): %s\n", venda.Desconto, venda.ValorDesconto().Multiplicar(-1))
+		}
+		if venda.PontosResgatados > 0 {
+			fmt.Printf("RESGATE DE PONTOS (%d pontos): %s\n", venda.PontosResgatados, venda.ValorResgate.Multiplicar(-1))
+		}
+		fmt.Println("TOTAL:", venda.Total())
+		fmt.Println("\n0 -> SALVAR E VOLTAR")
+		fmt.Println("1 -> ADICIONAR ITEM")
+		fmt.Println("2 -> REMOVER ITEM")
+		fmt.Println("3 -> DESCONTO NO ITEM")
+		fmt.Println("4 -> DESCONTO NA VENDA")
+		fmt.Println("5 -> RESGATAR PONTOS")
+		fmt.Println("9 -> DESCARTAR ALTERAÇÕES")
+
+		opcao, _ := strconv.Atoi(lerLinha(scanner, "INFORME A SUA OPCAO: "))
+		switch opcao {
+		case 0:
+			if err := m.daoVenda.Atualizar(venda); err != nil {
+				mostrarErro("salvar a venda", err)
+				if errors.Is(err, data.ErrInvalido) {
+					continue // Permite corrigir a venda sem perder a edição.
+				}
+				return
+			}
+			fmt.Println("Venda atualizada com sucesso!")
+			return
+		case 1:
+			produto, qtd := m.lerItem(scanner, venda, encontrada)
+			venda.AdicionarItem(*produto, qtd)
+			m.aplicarPromocoes(venda)
+		case 2:
+			posicao, _ := strconv.Atoi(lerLinha(scanner, "Digite o número do item: "))
+			venda.RemoverItemPorPosicao(posicao - 1)
+			m.aplicarPromocoes(venda)
+		case 3:
+			posicao, _ := strconv.Atoi(lerLinha(scanner, "Digite o número do item: "))
+			m.lerDesconto(scanner, venda, "Desconto no item (vazio para manter, 0 para remover): ", func(v *entidades.Venda, d entidades.Desconto) error {
+				return v.AplicarDescontoItem(posicao-1, d)
+			})
+		case 4:
+			m.lerDesconto(scanner, venda, "Desconto na venda (vazio para manter, 0 para remover): ", (*entidades.Venda).AplicarDesconto)
+		case 5:
+			if venda.ClienteID == 0 {
+				fmt.Println("A venda não tem cliente identificado.")
+				continue
+			}
+			m.lerResgate(scanner, venda)
+		case 9:
+			return
+		default:
+			fmt.Print("OPÇÃO INVÁLIDA\n\n")
+		}
+	}
+}
+
+// lerDesconto lê um desconto e o aplica à venda pela função informada, até que seja aceito.
+// Um desconto só é aceito se estiver dentro do limite do operador, que é perguntado no
+// primeiro desconto da venda e novamente sempre que o limite for excedido, para que o
+// caixa possa chamar um gerente com limite maior. Uma linha vazia mantém a venda como está.
+//
+// O operador é apenas um nome digitado, sem senha: serve para aplicar o limite e registrar
+// quem concedeu o desconto, mas não é uma autenticação.
+func (m *MenuVenda) lerDesconto(scanner *bufio.Scanner, venda *entidades.Venda, texto string, aplicar func(*entidades.Venda, entidades.Desconto) error) {
+	for {
+		linha := lerLinha(scanner, texto)
+		if linha == "" {
+			return
+		}
+		desconto, err := entidades.ParseDesconto(linha)
+		if err != nil {
+			fmt.Println("Desconto inválido. Use, por exemplo, 10% ou 5,00.")
+			continue
+		}
+		if venda.Operador == "" && !desconto.Vazio() {
+			venda.Operador = lerLinha(scanner, "Digite o operador que autoriza o desconto: ")
+		}
+
+		copia := venda.Clonar()
+		err = aplicar(copia, desconto)
+		if err == nil {
+			err = data.GetLimitesDesconto().Verificar(copia)
+		}
+		if errors.Is(err, data.ErrDescontoExcedido) {
+			mostrarErro("aplicar o desconto", err)
+			operador := lerLinha(scanner, "Digite o operador que autoriza o desconto (vazio para informar outro desconto): ")
+			if operador == "" {
+				continue
+			}
+			copia.Operador = operador
+			err = data.GetLimitesDesconto().Verificar(copia)
+		}
+		if err != nil {
+			mostrarErro("aplicar o desconto", err)
 			continue
 		}
-		break
+		*venda = *copia
+		return
+	}
+}
+
+// aplicarPromocoes recalcula as promoções vigentes da venda e informa as que foram aplicadas.
+func (m *MenuVenda) aplicarPromocoes(venda *entidades.Venda) {
+	agora := time.Now()
+	venda.AplicarPromocoes(m.daoPromocao.Vigentes(agora), agora)
+	for _, item := range venda.GetItens() {
+		if len(item.Promocoes) > 0 {
+			fmt.Printf("Promoção %s em %s: %s\n", strings.Join(item.Promocoes, " + "), item.Produto.GetNome(), item.DescontoPromocao.Multiplicar(-1))
+		}
 	}
+}
 
-	m.daoVenda.Remover(id)
+// descontoNoUltimoItem aplica o desconto ao último item incluído na venda.
+func descontoNoUltimoItem(venda *entidades.Venda, desconto entidades.Desconto) error {
+	return venda.AplicarDescontoItem(len(venda.GetItens())-1, desconto)
 }